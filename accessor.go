@@ -1,9 +1,9 @@
 package godiff
 
 import (
-	"fmt"
 	"github.com/viant/xunsafe"
 	"reflect"
+	"time"
 	"unsafe"
 )
 
@@ -18,10 +18,10 @@ const (
 )
 
 type accessor struct {
-	pos      int
-	deref    bool
-	normType reflect.Type //if from,to data type is  different (i.e. int64 vs uint64), norm type is used to reconcile the types
-	xType    *xunsafe.Type
+	pos    int
+	deref  bool
+	isTime bool
+	xType  *xunsafe.Type
 	nullifierKind
 	*xunsafe.Field
 }
@@ -33,32 +33,44 @@ func (d *accessor) normalize(value interface{}) (interface{}, error) {
 	if d.deref {
 		value = d.xType.Deref(value)
 	}
+	return value, nil
+}
 
-	if d.normType != nil {
-		ptr := xunsafe.AsPointer(value)
-		switch d.normType.Kind() {
-		case reflect.Int:
-			return *(*int)(ptr), nil
-		case reflect.Int32:
-			return *(*int32)(ptr), nil
-		case reflect.Int64:
-			return *(*int64)(ptr), nil
-		case reflect.Int16:
-			return *(*int16)(ptr), nil
-		case reflect.Uint8:
-			return *(*uint8)(ptr), nil
-		default:
-			return nil, fmt.Errorf("unsupported norm type: %T", value)
-		}
+//IsNil returns true if struct pointer or field value is nil, only pointer, map, slice and interface values can be nil,
+//except time value that is nil when zero
+func (d *accessor) IsNil(ptr unsafe.Pointer) bool {
+	if ptr == nil {
+		return true
 	}
-	return value, nil
+	if d.isTime {
+		return (*time.Time)(d.Field.Pointer(ptr)).IsZero()
+	}
+	switch d.Field.Type.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return d.Field.IsNil(ptr)
+	}
+	return false
+}
+
+//IsZero returns true if field value is nil or zero
+func (d *accessor) IsZero(ptr unsafe.Pointer) bool {
+	if d.IsNil(ptr) {
+		return true
+	}
+	return reflect.NewAt(d.Field.Type, d.Field.Pointer(ptr)).Elem().IsZero()
 }
 
 func (d *accessor) Value(ptr unsafe.Pointer) (value interface{}, err error) {
 	if d.IsNil(ptr) {
 		return nil, nil
 	}
-	value = d.Field.Value(ptr)
+	switch d.Field.Type.Kind() {
+	case reflect.Ptr, reflect.Map:
+		//pointer shaped value is read with reflect, it is stored directly in the interface
+		value = reflect.NewAt(d.Field.Type, d.Field.Pointer(ptr)).Elem().Interface()
+	default:
+		value = d.Field.Value(ptr)
+	}
 	if value, err = d.normalize(value); err != nil {
 		return nil, err
 	}
@@ -104,6 +116,7 @@ func newAccessor(pos int, field *xunsafe.Field, tag *Tag) accessor {
 		Field:         field,
 		deref:         field.Type.Kind() == reflect.Ptr && (structType(field.Type.Elem()) == nil || isTimeType(field.Type.Elem())),
 		nullifierKind: getNullifierKind(tag, field),
+		isTime:        field.Type == timeType,
 	}
 	if result.deref {
 		result.xType = xunsafe.NewType(field.Type.Elem())
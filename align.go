@@ -0,0 +1,290 @@
+package godiff
+
+import "sort"
+
+//AlignLCS defines longest common subsequence slice elements alignment
+const AlignLCS = "lcs"
+
+//alignment represents slice elements alignment
+type alignment struct {
+	matched [][2]int //from, to positions of equal elements keeping relative order
+	updated [][2]int //from, to positions of elements compared as updates
+	moved   [][2]int //from, to positions of equal elements changing relative order
+	deleted []int    //from positions of removed elements
+	created []int    //to positions of added elements
+}
+
+//align aligns from and to slice elements with longest common subsequence, unmatched equal elements are reported as moved
+//(if detectMoves is set), elements between matched ones are paired as updates, the remaining ones are reported as removed or added
+func align(fromLen, toLen int, equal func(i, j int) (bool, error), detectMoves bool) (*alignment, error) {
+	matched, err := lcs(fromLen, toLen, equal)
+	if err != nil {
+		return nil, err
+	}
+	result := &alignment{matched: matched}
+	movedFrom := map[int]bool{}
+	movedTo := map[int]bool{}
+	if detectMoves {
+		if err = result.detectMoves(fromLen, toLen, equal, movedFrom, movedTo); err != nil {
+			return nil, err
+		}
+	}
+	i, j := 0, 0
+	for _, pair := range append(matched, [2]int{fromLen, toLen}) {
+		var fromGap, toGap []int
+		for ; i < pair[0]; i++ {
+			if !movedFrom[i] {
+				fromGap = append(fromGap, i)
+			}
+		}
+		for ; j < pair[1]; j++ {
+			if !movedTo[j] {
+				toGap = append(toGap, j)
+			}
+		}
+		k := 0
+		for ; k < len(fromGap) && k < len(toGap); k++ {
+			result.updated = append(result.updated, [2]int{fromGap[k], toGap[k]})
+		}
+		result.deleted = append(result.deleted, fromGap[k:]...)
+		result.created = append(result.created, toGap[k:]...)
+		i, j = pair[0]+1, pair[1]+1
+	}
+	return result, nil
+}
+
+//detectMoves pairs equal elements outside of longest common subsequence
+func (a *alignment) detectMoves(fromLen, toLen int, equal func(i, j int) (bool, error), movedFrom, movedTo map[int]bool) error {
+	matchedFrom := map[int]bool{}
+	matchedTo := map[int]bool{}
+	for _, pair := range a.matched {
+		matchedFrom[pair[0]], matchedTo[pair[1]] = true, true
+	}
+	for i := 0; i < fromLen; i++ {
+		if matchedFrom[i] {
+			continue
+		}
+		for j := 0; j < toLen; j++ {
+			if matchedTo[j] || movedTo[j] {
+				continue
+			}
+			ok, err := equal(i, j)
+			if err != nil {
+				return err
+			}
+			if ok {
+				a.moved = append(a.moved, [2]int{i, j})
+				movedFrom[i], movedTo[j] = true, true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+//moves returns moves reordering kept elements from 'from' to 'to' order, kept elements are given as from, to positions,
+//only elements flagged as moved change position, each move is relative to kept elements after previous moves,
+//moves are returned (for each moved element) as kept element, from and to positions
+func moves(kept [][2]int, moved []bool) [][3]int {
+	byFrom := make([]int, len(kept))
+	byTo := make([]int, len(kept))
+	for k := range kept {
+		byFrom[k], byTo[k] = k, k
+	}
+	sort.Slice(byFrom, func(i, j int) bool { return kept[byFrom[i]][0] < kept[byFrom[j]][0] })
+	sort.Slice(byTo, func(i, j int) bool { return kept[byTo[i]][1] < kept[byTo[j]][1] })
+	list := byFrom
+	var result [][3]int
+	for q, k := range byTo {
+		if !moved[k] {
+			continue
+		}
+		position := indexOf(list, k)
+		list = append(list[:position], list[position+1:]...)
+		target := 0
+		if q > 0 {
+			target = indexOf(list, byTo[q-1]) + 1
+		}
+		list = append(list[:target], append([]int{k}, list[target:]...)...)
+		result = append(result, [3]int{k, position, target})
+	}
+	return result
+}
+
+//movePositions returns moves of moved elements (from, to positions) as moved element, from and to positions in sequence order,
+//positions are relative to the slice after deleted elements ('from' positions) were removed, before created elements
+//('to' positions) are added, elements that are not moved keep their relative order
+func movePositions(deleted, created map[int]bool, moved [][2]int) [][3]int {
+	movedFrom := map[int]bool{}
+	movedTo := map[int]bool{}
+	last := 0
+	for _, pair := range moved {
+		movedFrom[pair[0]], movedTo[pair[1]] = true, true
+		last = maxInt(last, maxInt(pair[0], pair[1]))
+	}
+	for index := range deleted {
+		last = maxInt(last, index)
+	}
+	for index := range created {
+		last = maxInt(last, index)
+	}
+	//elements that are not moved are paired in order, elements past the last position do not affect moves
+	size := last + 1 + len(deleted) + len(created) + 2*len(moved)
+	var stableFrom, stableTo []int
+	for i := 0; i < size; i++ {
+		if !deleted[i] && !movedFrom[i] {
+			stableFrom = append(stableFrom, i)
+		}
+		if !created[i] && !movedTo[i] {
+			stableTo = append(stableTo, i)
+		}
+	}
+	kept := append([][2]int{}, moved...)
+	for k := 0; k < len(stableFrom) && k < len(stableTo); k++ {
+		kept = append(kept, [2]int{stableFrom[k], stableTo[k]})
+	}
+	flags := make([]bool, len(kept))
+	for k := range moved {
+		flags[k] = true
+	}
+	return moves(kept, flags)
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func indexOf(list []int, item int) int {
+	for i, candidate := range list {
+		if candidate == item {
+			return i
+		}
+	}
+	return -1
+}
+
+//lcs returns from, to positions of longest common subsequence elements, it uses Myers linear space algorithm,
+//so that equal is called O((fromLen+toLen)*D) times, where D is the number of removed and added elements
+func lcs(fromLen, toLen int, equal func(i, j int) (bool, error)) ([][2]int, error) {
+	aLCS := &sequences{equal: equal, cache: map[[2]int]bool{}}
+	aLCS.match(0, fromLen, 0, toLen)
+	return aLCS.matched, aLCS.err
+}
+
+//sequences represents longest common subsequence state
+type sequences struct {
+	equal   func(i, j int) (bool, error)
+	cache   map[[2]int]bool //element comparison can be expensive, i.e. nested item diff
+	matched [][2]int
+	err     error
+}
+
+func (s *sequences) equals(i, j int) bool {
+	if s.err != nil {
+		return false
+	}
+	key := [2]int{i, j}
+	if result, ok := s.cache[key]; ok {
+		return result
+	}
+	result, err := s.equal(i, j)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	s.cache[key] = result
+	return result
+}
+
+//match adds matched elements of from[fromStart:fromEnd] and to[toStart:toEnd] in order
+func (s *sequences) match(fromStart, fromEnd, toStart, toEnd int) {
+	for fromStart < fromEnd && toStart < toEnd && s.equals(fromStart, toStart) {
+		s.matched = append(s.matched, [2]int{fromStart, toStart})
+		fromStart, toStart = fromStart+1, toStart+1
+	}
+	var suffix [][2]int
+	for fromEnd > fromStart && toEnd > toStart && s.equals(fromEnd-1, toEnd-1) {
+		fromEnd, toEnd = fromEnd-1, toEnd-1
+		suffix = append(suffix, [2]int{fromEnd, toEnd})
+	}
+	if fromStart < fromEnd && toStart < toEnd && s.err == nil {
+		if x, y, ok := s.split(fromStart, fromEnd, toStart, toEnd); ok {
+			s.match(fromStart, x, toStart, y)
+			s.match(x, fromEnd, y, toEnd)
+		}
+	}
+	for i := len(suffix) - 1; i >= 0; i-- {
+		s.matched = append(s.matched, suffix[i])
+	}
+}
+
+//split returns middle snake position dividing shortest edit script in halves, ok is false if there are no equal elements
+func (s *sequences) split(fromStart, fromEnd, toStart, toEnd int) (int, int, bool) {
+	n, m := fromEnd-fromStart, toEnd-toStart
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	odd := delta%2 != 0
+	var kStart, kEnd, rStart, rEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + kStart; k <= d-kEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.equals(fromStart+x, toStart+y) {
+				x, y = x+1, y+1
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				kEnd += 2
+			case y > m:
+				kStart += 2
+			case odd:
+				if r := offset + delta - k; r >= 0 && r < len(backward) && backward[r] != -1 && x >= n-backward[r] {
+					return fromStart + x, toStart + y, true
+				}
+			}
+		}
+		for k := -d + rStart; k <= d-rEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.equals(fromEnd-x-1, toEnd-y-1) {
+				x, y = x+1, y+1
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				rEnd += 2
+			case y > m:
+				rStart += 2
+			case !odd:
+				if f := offset + delta - k; f >= 0 && f < len(forward) && forward[f] != -1 {
+					fx := forward[f]
+					if fx >= n-x {
+						return fromStart + fx, toStart + fx - (f - offset), true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,79 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_AlignLCS(t *testing.T) {
+
+	type Step struct {
+		Name string
+		Args int
+	}
+	type Pipeline struct {
+		ID    int
+		Nums  []int         `diff:"align=lcs"`
+		Steps []Step        `diff:"align=lcs"`
+		Any   []interface{} `diff:"align=lcs"`
+	}
+
+	var long = make([]int, 100)
+	for i := range long {
+		long[i] = i + 1
+	}
+	var testCases = []struct {
+		description string
+		from        *Pipeline
+		to          *Pipeline
+		expect      *ChangeLog
+	}{
+		{
+			description: "insert at front",
+			from:        &Pipeline{Nums: long},
+			to:          &Pipeline{Nums: append([]int{0}, long...)},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Nums").Element(0), To: 0},
+			}},
+		},
+		{
+			description: "insert, delete and update",
+			from:        &Pipeline{Steps: []Step{{"a", 1}, {"b", 1}, {"c", 1}, {"d", 1}}},
+			to:          &Pipeline{Steps: []Step{{"x", 1}, {"a", 1}, {"c", 2}, {"d", 1}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Steps").Element(1).Field("Name"), From: "b", To: "c"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Steps").Element(1).Field("Args"), From: 1, To: 2},
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Steps").Element(2), From: Step{"c", 1}},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Steps").Element(0), To: Step{"x", 1}},
+			}},
+		},
+		{
+			description: "interface elements",
+			from:        &Pipeline{Any: []interface{}{"a", "b", "c"}},
+			to:          &Pipeline{Any: []interface{}{"b", "c", "d"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Any").Element(0), From: "a"},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Any").Element(2), To: "d"},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Pipeline{}), reflect.TypeOf(&Pipeline{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		if !assert.EqualValues(t, testCase.expect, changeLog, testCase.description) {
+			continue
+		}
+		target := cloneValue(reflect.ValueOf(testCase.from)).Interface()
+		if assert.Nil(t, changeLog.Apply(target), testCase.description) {
+			assert.EqualValues(t, testCase.to, target, testCase.description)
+		}
+	}
+
+	_, err = ParseTag("align=myers")
+	assert.NotNil(t, err)
+}
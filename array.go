@@ -0,0 +1,32 @@
+package godiff
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//newArrayDiffer creates fixed size array differ, arrays are compared element-wise as slices
+func newArrayDiffer(from, to reflect.Type, config *Config, tag *Tag) (*Differ, error) {
+	from, to = arrayType(from), arrayType(to)
+	if from == nil || to == nil {
+		return nil, fmt.Errorf("invalid array types: %v, %v", from, to)
+	}
+	differ, err := newSliceDiffer(reflect.SliceOf(from.Elem()), reflect.SliceOf(to.Elem()), config, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &Differ{config: config, sliceDiffer: differ, decoder: arrayAsSlice}, nil
+}
+
+//arrayAsSlice returns array (or array pointer) copy as a slice pointer
+func arrayAsSlice(value interface{}) interface{} {
+	array := indirect(reflect.ValueOf(value))
+	if !array.IsValid() || array.Kind() != reflect.Array {
+		return value
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(array.Type().Elem()), array.Len(), array.Len())
+	reflect.Copy(slice, array)
+	result := reflect.New(slice.Type())
+	result.Elem().Set(slice)
+	return result.Interface()
+}
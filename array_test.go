@@ -0,0 +1,95 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_Array(t *testing.T) {
+
+	type Point struct {
+		X int
+		Y int
+	}
+	type Item struct {
+		ID   int
+		Name string
+	}
+	type Shape struct {
+		ID     int
+		Hash   [4]byte
+		Coords [2]Point
+		Labels [3]string `diff:"sort=true"`
+		Items  [2]Item   `diff:"indexBy=ID"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Shape
+		to          *Shape
+		expect      *ChangeLog
+		skipApply   bool
+	}{
+		{
+			description: "primitive elements",
+			from:        &Shape{Hash: [4]byte{1, 2, 3, 4}},
+			to:          &Shape{Hash: [4]byte{1, 2, 5, 4}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Hash").Element(2), From: byte(3), To: byte(5)},
+			}},
+		},
+		{
+			description: "struct elements",
+			from:        &Shape{Coords: [2]Point{{1, 2}, {3, 4}}},
+			to:          &Shape{Coords: [2]Point{{1, 2}, {3, 7}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Coords").Element(1).Field("Y"), From: 4, To: 7},
+			}},
+		},
+		{
+			description: "sorted elements",
+			from:        &Shape{Labels: [3]string{"c", "a", "b"}},
+			to:          &Shape{Labels: [3]string{"a", "b", "c"}},
+			expect:      &ChangeLog{},
+			skipApply:   true, //sorted comparison ignores elements order, applied change log does not reorder them
+		},
+		{
+			description: "indexed elements",
+			from:        &Shape{Items: [2]Item{{1, "a"}, {2, "b"}}},
+			to:          &Shape{Items: [2]Item{{3, "c"}, {1, "a"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Items").KeyedElement("ID", 2, 1, -1), From: Item{2, "b"}},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Items").KeyedElement("ID", 3, -1, 0), To: Item{3, "c"}},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Shape{}), reflect.TypeOf(&Shape{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		if !assert.EqualValues(t, testCase.expect, changeLog, testCase.description) {
+			continue
+		}
+		if testCase.skipApply {
+			continue
+		}
+		if assert.Nil(t, changeLog.Apply(testCase.from), testCase.description) {
+			assert.EqualValues(t, testCase.to, testCase.from, testCase.description)
+		}
+	}
+
+	rootDiffer, err := New(reflect.TypeOf([3]int{}), reflect.TypeOf([3]int{}))
+	if assert.Nil(t, err) {
+		changeLog := rootDiffer.Diff([3]int{1, 2, 3}, [3]int{1, 0, 3})
+		assert.EqualValues(t, []*Change{{Type: ChangeTypeUpdate, Path: (&Path{}).Element(1), From: 2, To: 0}}, changeLog.Changes)
+	}
+	mapDiffer, err := New(reflect.TypeOf(map[string][2]int{}), reflect.TypeOf(map[string][2]int{}))
+	if assert.Nil(t, err) {
+		changeLog := mapDiffer.Diff(map[string][2]int{"a": {1, 2}}, map[string][2]int{"a": {1, 3}})
+		assert.EqualValues(t, []*Change{{Type: ChangeTypeUpdate, Path: (&Path{}).Entry("a").Element(1), From: 2, To: 3}}, changeLog.Changes)
+	}
+}
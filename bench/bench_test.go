@@ -42,6 +42,30 @@ func Benchmark_GoDiff(b *testing.B) {
 	}
 }
 
+type Pipeline struct {
+	Id    int
+	Steps []Flag `diff:"align=lcs"`
+}
+
+var benchAlignDiff, _ = godiff.New(reflect.TypeOf(&Pipeline{}), reflect.TypeOf(&Pipeline{}))
+
+func Benchmark_GoDiffAlignLCS(b *testing.B) {
+	pipeline1 := &Pipeline{Id: 1}
+	pipeline2 := &Pipeline{Id: 1, Steps: []Flag{{Value: -1}}}
+	for i := 0; i < 1000; i++ {
+		pipeline1.Steps = append(pipeline1.Steps, Flag{Value: i})
+		if i%100 != 50 {
+			pipeline2.Steps = append(pipeline2.Steps, Flag{Value: i})
+		}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		changeLog := benchAlignDiff.Diff(pipeline1, pipeline2)
+		assert.True(b, len(changeLog.Changes) == 11)
+	}
+}
+
 /*
 var s3LabDif, _ = diff.NewDiffer()
 
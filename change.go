@@ -7,6 +7,8 @@ const (
 	ChangeTypeUpdate = ChangeType("update")
 	//ChangeTypeDelete defines delete change type
 	ChangeTypeDelete = ChangeType("delete")
+	//ChangeTypeMove defines slice element move change type, From and To are element positions in from and to slices
+	ChangeTypeMove = ChangeType("move")
 )
 
 type (
@@ -32,5 +34,23 @@ type (
 		From  interface{}
 		To    interface{}
 		Error string `json:",omitempty"`
+
+		timeLayout string
+		container  *Change //create or delete of the whole container (struct, slice, map or slice element) the change is nested in
 	}
 )
+
+func (c *Change) invert() *Change {
+	result := &Change{Type: c.Type, Path: c.Path.inverted(), From: c.To, To: c.From, timeLayout: c.timeLayout}
+	switch c.Type {
+	case ChangeTypeCreate:
+		result.Type = ChangeTypeDelete
+	case ChangeTypeDelete:
+		result.Type = ChangeTypeCreate
+	case ChangeTypeMove:
+		if to, ok := c.To.(int); ok && c.Path.Kind == PathKindIndex {
+			result.Path = result.Path.at(to)
+		}
+	}
+	return result
+}
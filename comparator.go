@@ -1,10 +1,24 @@
 package godiff
 
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
 // Comparator an interface for comparison customization
 type Comparator interface {
 	Matches(from, to interface{}, tag *Tag) (bool, error)
 }
 
+//ComparatorFunc represents a function comparator
+type ComparatorFunc func(from, to interface{}, tag *Tag) (bool, error)
+
+//Matches returns true if from and to values are equal
+func (f ComparatorFunc) Matches(from, to interface{}, tag *Tag) (bool, error) {
+	return f(from, to, tag)
+}
+
 func matches(from, to interface{}) bool {
 	if from == nil {
 		if to == nil {
@@ -15,5 +29,76 @@ func matches(from, to interface{}) bool {
 	if to == nil {
 		return false
 	}
+	if !reflect.TypeOf(from).Comparable() {
+		return reflect.DeepEqual(from, to)
+	}
 	return from == to
 }
+
+//comparator returns comparator registered for the type or its pointer element type
+func (c *Config) comparator(t reflect.Type) Comparator {
+	if len(c.comparators) == 0 || t == nil {
+		return nil
+	}
+	if comparator, ok := c.comparators[t]; ok {
+		return comparator
+	}
+	if t.Kind() == reflect.Ptr {
+		return c.comparators[t.Elem()]
+	}
+	return nil
+}
+
+//isLeaf returns true if the type values are compared as a whole, rather than by their fields or elements
+func (c *Config) isLeaf(t reflect.Type) bool {
+	return c.comparator(t) != nil || isTimeType(t) || c.registry.equalComparator(t) != nil
+}
+
+//namedComparator returns comparator registered for the tag comparator name
+func (c *Config) namedComparator(tag *Tag) (Comparator, error) {
+	if tag == nil || tag.Comparator == "" {
+		return nil, nil
+	}
+	comparator, ok := c.namedComparators[tag.Comparator]
+	if !ok {
+		return nil, fmt.Errorf("unknown comparator: %v", tag.Comparator)
+	}
+	return comparator, nil
+}
+
+//matches returns true if from and to values are equal, tag named comparator is used first, then registered type comparator
+//and default comparator
+func (c *Config) matches(from, to interface{}, tag *Tag) (bool, error) {
+	if from == nil || to == nil {
+		return matches(from, to), nil
+	}
+	comparator, err := c.namedComparator(tag)
+	if err != nil {
+		return false, err
+	}
+	if comparator != nil {
+		return comparator.Matches(from, to, tag)
+	}
+	if comparator := c.comparator(reflect.TypeOf(from)); comparator != nil {
+		return comparator.Matches(from, to, tag)
+	}
+	if c.defaultComparator != nil {
+		return c.defaultComparator.Matches(from, to, tag)
+	}
+	var within time.Duration
+	if tag != nil {
+		within = tag.Within
+	}
+	if matched, ok := matchesTime(from, to, c.timeLayout(tag), within); ok {
+		return matched, nil
+	}
+	if precision := c.precision(tag); precision != nil {
+		if matched, ok := matchesWithPrecision(from, to, *precision, tag != nil && tag.decoded); ok {
+			return matched, nil
+		}
+	}
+	if comparator := c.registry.equalComparator(reflect.TypeOf(from)); comparator != nil {
+		return comparator.Matches(from, to, tag)
+	}
+	return matches(from, to), nil
+}
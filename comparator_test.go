@@ -0,0 +1,162 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffer_Comparator(t *testing.T) {
+
+	type Money struct {
+		Units    int
+		Currency string
+	}
+	type Host struct {
+		Name   string
+		IP     net.IP
+		Price  Money
+		Prices []Money
+	}
+
+	moneyComparator := ComparatorFunc(func(from, to interface{}, tag *Tag) (bool, error) {
+		fromMoney, toMoney := from.(Money), to.(Money)
+		return fromMoney.Units == toMoney.Units && strings.EqualFold(fromMoney.Currency, toMoney.Currency), nil
+	})
+	ipComparator := ComparatorFunc(func(from, to interface{}, tag *Tag) (bool, error) {
+		return from.(net.IP).Equal(to.(net.IP)), nil
+	})
+	foldComparator := ComparatorFunc(func(from, to interface{}, tag *Tag) (bool, error) {
+		fromText, ok := from.(string)
+		if !ok {
+			return from == to, nil
+		}
+		toText, _ := to.(string)
+		return strings.EqualFold(fromText, toText), nil
+	})
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		options     []ConfigOption
+		expect      *ChangeLog
+	}{
+		{
+			description: "type comparator - no changes",
+			from:        Host{Name: "a", IP: net.ParseIP("10.0.0.1").To4(), Price: Money{1, "usd"}, Prices: []Money{{1, "usd"}}},
+			to:          Host{Name: "a", IP: net.ParseIP("10.0.0.1"), Price: Money{1, "USD"}, Prices: []Money{{1, "USD"}}},
+			options:     []ConfigOption{WithComparator(reflect.TypeOf(Money{}), moneyComparator), WithComparator(reflect.TypeOf(net.IP{}), ipComparator)},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "type comparator - changes",
+			from:        Host{Name: "a", IP: net.ParseIP("10.0.0.1"), Price: Money{1, "usd"}, Prices: []Money{{1, "usd"}}},
+			to:          Host{Name: "a", IP: net.ParseIP("10.0.0.2"), Price: Money{2, "USD"}, Prices: []Money{{1, "EUR"}}},
+			options:     []ConfigOption{WithComparator(reflect.TypeOf(Money{}), moneyComparator), WithComparator(reflect.TypeOf(net.IP{}), ipComparator)},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("IP"), From: net.ParseIP("10.0.0.1"), To: net.ParseIP("10.0.0.2")},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Price"), From: Money{1, "usd"}, To: Money{2, "USD"}},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Prices").Element(0), From: Money{1, "usd"}, To: Money{1, "EUR"}},
+			}},
+		},
+		{
+			description: "root type comparator",
+			from:        Money{1, "usd"},
+			to:          Money{1, "USD"},
+			options:     []ConfigOption{WithComparator(reflect.TypeOf(Money{}), moneyComparator)},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "default comparator",
+			from:        Host{Name: "abc", Price: Money{1, "usd"}},
+			to:          Host{Name: "ABC", Price: Money{1, "USD"}},
+			options:     []ConfigOption{WithDefaultComparator(foldComparator)},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "default comparator with interface slice",
+			from:        []interface{}{Money{1, "usd"}},
+			to:          []interface{}{Money{1, "USD"}},
+			options:     []ConfigOption{WithDefaultComparator(foldComparator)},
+			expect:      &ChangeLog{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to), testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+}
+
+func TestDiffer_NamedComparator(t *testing.T) {
+
+	type Account struct {
+		ID    int
+		Email string   `diff:"comparator=fold"`
+		Tags  []string `diff:"comparator=set"`
+	}
+	type Invalid struct {
+		Email string `diff:"comparator=unknown"`
+	}
+
+	foldComparator := ComparatorFunc(func(from, to interface{}, tag *Tag) (bool, error) {
+		return strings.EqualFold(from.(string), to.(string)), nil
+	})
+	setComparator := ComparatorFunc(func(from, to interface{}, tag *Tag) (bool, error) {
+		fromSet := map[string]bool{}
+		for _, item := range from.([]string) {
+			fromSet[item] = true
+		}
+		toSet := map[string]bool{}
+		for _, item := range to.([]string) {
+			if !fromSet[item] {
+				return false, nil
+			}
+			toSet[item] = true
+		}
+		return len(fromSet) == len(toSet), nil
+	})
+	options := []ConfigOption{WithNamedComparator("fold", foldComparator), WithNamedComparator("set", setComparator)}
+
+	var testCases = []struct {
+		description string
+		from        *Account
+		to          *Account
+		expect      *ChangeLog
+	}{
+		{
+			description: "no changes",
+			from:        &Account{ID: 1, Email: "john@example.com", Tags: []string{"a", "b"}},
+			to:          &Account{ID: 1, Email: "John@Example.com", Tags: []string{"b", "a", "a"}},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "changes",
+			from:        &Account{ID: 1, Email: "john@example.com", Tags: []string{"a", "b"}},
+			to:          &Account{ID: 1, Email: "jack@example.com", Tags: []string{"a", "c"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Email"), From: "john@example.com", To: "jack@example.com"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Tags"), From: []string{"a", "b"}, To: []string{"a", "c"}},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Account{}), reflect.TypeOf(&Account{}), options...)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+
+	_, err = New(reflect.TypeOf(&Invalid{}), reflect.TypeOf(&Invalid{}), options...)
+	assert.NotNil(t, err)
+}
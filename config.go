@@ -8,8 +8,13 @@ type Config struct {
 	NullifyEmpty *bool
 	TagName      string //diff by default
 	StrictMode   bool   //non-strict mode allows string with non-string matches
+	Precision    *int   //float decimal places precision, overridden by precision tag
 	tag          *Tag
 	registry     *Registry
+
+	comparators       map[reflect.Type]Comparator
+	namedComparators  map[string]Comparator
+	defaultComparator Comparator
 }
 
 //Init init config
@@ -21,3 +26,12 @@ func (c *Config) Init() {
 		c.registry = &Registry{differs: map[reflect.Type]map[reflect.Type]*Differ{}}
 	}
 }
+
+func newConfig(opts []ConfigOption) *Config {
+	config := &Config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	config.Init()
+	return config
+}
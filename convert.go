@@ -0,0 +1,117 @@
+package godiff
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+//kindMismatch returns true if from and to basic kind field values need conversion to be compared,
+//in strict mode string with non-string field returns also an error, reported only when converted values are not equal
+func kindMismatch(from, to reflect.Type, config *Config) (convert bool, err error) {
+	from, to = derefType(from), derefType(to)
+	if from == to || !isBasicKind(from.Kind()) || !isBasicKind(to.Kind()) {
+		return false, nil
+	}
+	if (from.Kind() == reflect.String) != (to.Kind() == reflect.String) && config.StrictMode {
+		return true, fmt.Errorf("type mismatch: %v vs %v", from.String(), to.String())
+	}
+	return true, nil
+}
+
+//convertKinds converts from and to values of different kinds to comparable values,
+//string value is parsed with the other value kind
+func convertKinds(from, to interface{}) (interface{}, interface{}) {
+	fromValue := indirect(reflect.ValueOf(from))
+	toValue := indirect(reflect.ValueOf(to))
+	if !fromValue.IsValid() || !toValue.IsValid() {
+		return from, to
+	}
+	from, to = basicValue(fromValue), basicValue(toValue)
+	fromText, isFromText := from.(string)
+	toText, isToText := to.(string)
+	switch {
+	case isFromText && !isToText:
+		if converted, ok := parseBasic(fromText, to); ok {
+			from = converted
+		}
+	case isToText && !isFromText:
+		if converted, ok := parseBasic(toText, from); ok {
+			to = converted
+		}
+	}
+	if _, ok := from.(float64); ok {
+		to = asFloat64(to)
+	} else if _, ok = to.(float64); ok {
+		from = asFloat64(from)
+	}
+	return from, to
+}
+
+//basicValue returns basic kind value as bool, string, int64, uint64 (if exceeds int64) or float64
+func basicValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.String:
+		return value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if unsigned := value.Uint(); unsigned <= uint64(1<<63-1) {
+			return int64(unsigned)
+		}
+		return value.Uint()
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	return value.Interface()
+}
+
+//parseBasic parses text as basic value type
+func parseBasic(text string, basic interface{}) (interface{}, bool) {
+	var result interface{}
+	var err error
+	switch basic.(type) {
+	case bool:
+		result, err = strconv.ParseBool(text)
+	case int64:
+		if result, err = strconv.ParseInt(text, 10, 64); err != nil {
+			result, err = strconv.ParseFloat(text, 64)
+		}
+	case uint64:
+		result, err = strconv.ParseUint(text, 10, 64)
+	case float64:
+		result, err = strconv.ParseFloat(text, 64)
+	default:
+		return nil, false
+	}
+	return result, err == nil
+}
+
+func asFloat64(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case int64:
+		return float64(actual)
+	case uint64:
+		return float64(actual)
+	}
+	return value
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+func isBasicKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_KindConversion(t *testing.T) {
+
+	type Entity struct {
+		ID     int64
+		Price  float64
+		Active bool
+		Count  uint8
+		Name   string
+	}
+	type DTO struct {
+		ID     string
+		Price  string
+		Active string
+		Count  int
+		Name   string
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Entity
+		to          *DTO
+		options     []ConfigOption
+		expect      *ChangeLog
+	}{
+		{
+			description: "non-strict - no changes",
+			from:        &Entity{ID: 12, Price: 1.5, Active: true, Count: 3, Name: "abc"},
+			to:          &DTO{ID: "12", Price: "1.50", Active: "true", Count: 3, Name: "abc"},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "non-strict - zero values",
+			from:        &Entity{ID: 0, Price: 0, Count: 0},
+			to:          &DTO{ID: "0", Price: "0", Active: "false", Count: 0},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "non-strict - changes to zero values",
+			from:        &Entity{ID: 12, Count: 3, Name: "abc"},
+			to:          &DTO{ID: "0", Price: "0", Active: "false", Count: 0, Name: ""},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("ID"), From: int64(12), To: "0"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Count"), From: uint8(3), To: 0},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Name"), From: "abc", To: ""},
+			}},
+		},
+		{
+			description: "non-strict - changes",
+			from:        &Entity{ID: 12, Price: 1.5, Active: true, Count: 3, Name: "abc"},
+			to:          &DTO{ID: "13", Price: "x", Active: "false", Count: 4, Name: "abc"},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("ID"), From: int64(12), To: "13"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Price"), From: 1.5, To: "x"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Active"), From: true, To: "false"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Count"), From: uint8(3), To: 4},
+			}},
+		},
+		{
+			description: "strict - equal values",
+			from:        &Entity{ID: 12, Price: 1.5, Active: true, Count: 3, Name: "abc"},
+			to:          &DTO{ID: "12", Price: "1.50", Active: "true", Count: 3, Name: "abc"},
+			options:     []ConfigOption{WithStrictMode(true)},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "strict - unequal values",
+			from:        &Entity{ID: 12, Price: 1.5, Active: true, Count: 3, Name: "abc"},
+			to:          &DTO{ID: "13", Price: "1.5", Active: "false", Count: 3, Name: "abc"},
+			options:     []ConfigOption{WithStrictMode(true)},
+			expect: &ChangeLog{Changes: []*Change{
+				{Path: (&Path{}).Field("ID"), Error: "type mismatch: int64 vs string"},
+				{Path: (&Path{}).Field("Active"), Error: "type mismatch: bool vs string"},
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to), testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+}
@@ -16,6 +16,7 @@ type Differ struct {
 	*mapDiffer
 	*sliceDiffer
 	*ifaceDiffer
+	*structMapDiffer
 }
 
 //Diff creates change log based on comparison from and to values
@@ -51,6 +52,7 @@ func (d *Differ) diff(changeLog *ChangeLog, aPath *Path, from, to interface{}, f
 		return nil
 	}
 
+	offset := changeLog.Size()
 	if d.structDiffer != nil {
 		err = d.structDiffer.diff(changeLog, aPath, from, to, fieldChangeType, options)
 	} else if d.sliceDiffer != nil {
@@ -59,17 +61,27 @@ func (d *Differ) diff(changeLog *ChangeLog, aPath *Path, from, to interface{}, f
 		err = d.ifaceDiffer.diff(changeLog, aPath, from, to, fieldChangeType, options)
 	} else if d.mapDiffer != nil {
 		err = d.mapDiffer.diff(changeLog, aPath, from, to, fieldChangeType, options)
+	} else if d.structMapDiffer != nil {
+		err = d.structMapDiffer.diff(changeLog, aPath, from, to, fieldChangeType, options)
 	} else {
-		if !matches(from, to) {
+		matched, err := d.config.matches(from, to, d.config.tag)
+		if err != nil {
+			return err
+		}
+		if !matched {
 			switch {
 			case fieldChangeType == ChangeTypeDelete && to == nil:
-				changeLog.AddDelete(aPath, from)
+				changeLog.addValue(ChangeTypeDelete, aPath, from, nil, d.config.timeLayout(d.config.tag))
 			case fieldChangeType == ChangeTypeCreate && from == nil:
-				changeLog.AddCreate(aPath, to)
+				changeLog.addValue(ChangeTypeCreate, aPath, nil, to, d.config.timeLayout(d.config.tag))
 			default:
-				changeLog.AddUpdate(aPath, from, to)
+				changeLog.addValue(ChangeTypeUpdate, aPath, from, to, d.config.timeLayout(d.config.tag))
 			}
 		}
+		return nil
+	}
+	if err == nil && d.decoder == nil {
+		changeLog.setContainer(offset, fieldChangeType, aPath, from, to)
 	}
 	return err
 }
@@ -86,10 +98,12 @@ func discoverChangeType(from interface{}, to interface{}) ChangeType {
 
 func (d *Differ) decodedSliceDiff() (*Differ, error) {
 	var err error
-	if d.sliceDiffer, err = newSliceDiffer(stringsType, stringsType, d.config, d.config.tag); err != nil {
+	tag := d.config.tag
+	clonedTag := tag.clone()
+	clonedTag.decoded = true
+	if d.sliceDiffer, err = newSliceDiffer(stringsType, stringsType, d.config, clonedTag); err != nil {
 		return nil, err
 	}
-	tag := d.config.tag
 	itemSplitter := splitter.New(strings.Split(tag.ItemSeparator, "|"), option.NewCase(false))
 	d.decoder = func(value interface{}) interface{} {
 		text, _ := value.(string)
@@ -110,6 +124,7 @@ func (d *Differ) decodedMapDiff() (*Differ, error) {
 	clonedTag := tag.clone()
 	clonedTag.PairDelimiter = ""
 	clonedTag.PairSeparator = ""
+	clonedTag.decoded = true
 	if d.mapDiffer, err = newMapDiffer(stringMapType, stringMapType, d.config, clonedTag); err != nil {
 		return nil, err
 	}
@@ -120,10 +135,8 @@ func (d *Differ) decodedMapDiff() (*Differ, error) {
 		items := itemSplitter.Split(text)
 		for _, item := range items {
 			pair := strings.Split(item, tag.PairSeparator)
-			pair[0] = tag.removeWhitespace(pair[0])
-			pair[1] = tag.removeWhitespace(pair[1])
 			if len(pair) == 2 {
-				ret[pair[0]] = pair[1]
+				ret[tag.removeWhitespace(pair[0])] = tag.removeWhitespace(pair[1])
 			}
 		}
 		return ret
@@ -147,16 +160,25 @@ func New(from, to reflect.Type, opts ...ConfigOption) (*Differ, error) {
 	var err error
 
 	switch {
+	case result.config.isLeaf(from) && result.config.isLeaf(to):
+		return result, nil
 	case structType(from) != nil && structType(to) != nil:
 		if result.structDiffer, err = newStructDiffer(from, to, result.config); err != nil {
 			return nil, err
 		}
 		return result, nil
+	case arrayType(from) != nil && arrayType(to) != nil:
+		return newArrayDiffer(from, to, result.config, result.config.tag)
 	case sliceType(from) != nil && sliceType(to) != nil:
 		if result.sliceDiffer, err = newSliceDiffer(from, to, result.config, result.config.tag); err != nil {
 			return nil, err
 		}
 		return result, nil
+	case isStructMap(from, to):
+		if result.structMapDiffer, err = newStructMapDiffer(from, to, result.config); err != nil {
+			return nil, err
+		}
+		return result, nil
 	case mapType(from) != nil && mapType(to) != nil:
 		if result.mapDiffer, err = newMapDiffer(from, to, result.config, result.config.tag); err != nil {
 			return nil, err
@@ -146,8 +146,8 @@ func TestNewDiffer(t *testing.T) {
 			from:        &Repeated{ID: 1, Records: []*Record{{ID: 12}}, Nums: []int{10, 2}},
 			to:          &Repeated{ID: 1, Records: []*Record{{ID: 23}}},
 			expect: &ChangeLog{Changes: []*Change{
-				{Type: "create", Path: &Path{Kind: 3, Path: &Path{Kind: 1, Path: &Path{}, Name: "Nums"}, Index: 0}, To: 10},
-				{Type: "create", Path: &Path{Kind: 3, Path: &Path{Kind: 1, Path: &Path{}, Name: "Nums"}, Index: 1}, To: 2},
+				{Type: "delete", Path: &Path{Kind: 3, Path: &Path{Kind: 1, Path: &Path{}, Name: "Nums"}, Index: 1}, From: 2},
+				{Type: "delete", Path: &Path{Kind: 3, Path: &Path{Kind: 1, Path: &Path{}, Name: "Nums"}, Index: 0}, From: 10},
 				{Type: "update", Path: &Path{Kind: 1, Path: &Path{Kind: 3, Path: &Path{Kind: 1, Path: &Path{}, Name: "Records"}, Index: 0}, Name: "ID"}, From: 12, To: 23},
 			}},
 		},
@@ -298,7 +298,7 @@ func TestNewDiffer(t *testing.T) {
 			},
 			expect: &ChangeLog{Changes: []*Change{
 
-				{Type: "create", Path: &Path{Kind: PathKindIndex, Index: 2, Path: &Path{Kind: PathKinField, Path: &Path{}, Name: "Entries"}},
+				{Type: "create", Path: &Path{Kind: PathKindIndex, Index: 2, Key: 3, IndexBy: "ID", FromIndex: -1, ToIndex: 2, Path: &Path{Kind: PathKinField, Path: &Path{}, Name: "Entries"}},
 					From: (interface{})(nil),
 					To:   &XEntry{ID: 3, Name: "Name 3"}},
 			}},
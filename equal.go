@@ -0,0 +1,57 @@
+package godiff
+
+import "reflect"
+
+//equalComparator compares values with type Equal(T) bool or Equal(*T) bool method
+type equalComparator struct {
+	method reflect.Method
+	ptr    bool //method has pointer receiver
+	ptrArg bool //method has pointer argument
+}
+
+//Matches returns true if from and to values are equal
+func (c *equalComparator) Matches(from, to interface{}, tag *Tag) (bool, error) {
+	fromValue := indirect(reflect.ValueOf(from))
+	toValue := indirect(reflect.ValueOf(to))
+	if !fromValue.IsValid() || !toValue.IsValid() || fromValue.Type() != toValue.Type() {
+		return matches(from, to), nil
+	}
+	receiver := fromValue
+	if c.ptr {
+		receiver = reflect.New(fromValue.Type())
+		receiver.Elem().Set(fromValue)
+	}
+	argument := toValue
+	if c.ptrArg {
+		argument = reflect.New(toValue.Type())
+		argument.Elem().Set(toValue)
+	}
+	result := c.method.Func.Call([]reflect.Value{receiver, argument})
+	return result[0].Bool(), nil
+}
+
+//newEqualComparator returns comparator for the type (or pointer element type) defining Equal(T) bool or Equal(*T) bool method, or nil
+func newEqualComparator(t reflect.Type) *equalComparator {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	if method, ok := t.MethodByName("Equal"); ok && isEqualMethod(method, t) {
+		return &equalComparator{method: method, ptrArg: method.Type.In(1) != t}
+	}
+	if method, ok := reflect.PtrTo(t).MethodByName("Equal"); ok && isEqualMethod(method, t) {
+		return &equalComparator{method: method, ptr: true, ptrArg: method.Type.In(1) != t}
+	}
+	return nil
+}
+
+//isEqualMethod returns true if method takes T or *T argument and returns bool
+func isEqualMethod(method reflect.Method, t reflect.Type) bool {
+	methodType := method.Type
+	if methodType.NumIn() != 2 || methodType.NumOut() != 1 || methodType.Out(0).Kind() != reflect.Bool {
+		return false
+	}
+	return methodType.In(1) == t || methodType.In(1) == reflect.PtrTo(t)
+}
@@ -0,0 +1,101 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testMoney struct {
+	Units    int
+	Currency string
+}
+
+func (m testMoney) Equal(other testMoney) bool {
+	return m.Units == other.Units && strings.EqualFold(m.Currency, other.Currency)
+}
+
+type testAddress struct {
+	ip net.IP
+}
+
+func (a *testAddress) Equal(other testAddress) bool {
+	return a.ip.Equal(other.ip)
+}
+
+type testPrice struct {
+	Cents    int
+	Currency string
+}
+
+func (p *testPrice) Equal(other *testPrice) bool {
+	return p.Cents == other.Cents && strings.EqualFold(p.Currency, other.Currency)
+}
+
+func TestDiffer_EqualMethod(t *testing.T) {
+
+	type Order struct {
+		ID      int
+		Price   testMoney
+		Address testAddress
+		Prices  []testMoney
+		Total   testPrice
+		Tax     *testPrice
+	}
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		expect      *ChangeLog
+	}{
+		{
+			description: "equal values",
+			from:        Order{ID: 1, Price: testMoney{1, "usd"}, Address: testAddress{net.ParseIP("10.0.0.1").To4()}, Prices: []testMoney{{1, "usd"}}},
+			to:          Order{ID: 1, Price: testMoney{1, "USD"}, Address: testAddress{net.ParseIP("10.0.0.1")}, Prices: []testMoney{{1, "USD"}}},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "changed values",
+			from:        Order{ID: 1, Price: testMoney{1, "usd"}, Address: testAddress{net.ParseIP("10.0.0.1")}, Prices: []testMoney{{1, "usd"}}},
+			to:          Order{ID: 1, Price: testMoney{2, "usd"}, Address: testAddress{net.ParseIP("10.0.0.2")}, Prices: []testMoney{{1, "eur"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Price"), From: testMoney{1, "usd"}, To: testMoney{2, "usd"}},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Address"), From: testAddress{net.ParseIP("10.0.0.1")}, To: testAddress{net.ParseIP("10.0.0.2")}},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Prices").Element(0), From: testMoney{1, "usd"}, To: testMoney{1, "eur"}},
+			}},
+		},
+		{
+			description: "equal pointer argument values",
+			from:        Order{ID: 1, Total: testPrice{100, "usd"}, Tax: &testPrice{10, "usd"}},
+			to:          Order{ID: 1, Total: testPrice{100, "USD"}, Tax: &testPrice{10, "USD"}},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "changed pointer argument values",
+			from:        Order{ID: 1, Total: testPrice{100, "usd"}, Tax: &testPrice{10, "usd"}},
+			to:          Order{ID: 1, Total: testPrice{200, "usd"}, Tax: &testPrice{10, "eur"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Total"), From: testPrice{100, "usd"}, To: testPrice{200, "usd"}},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Tax"), From: &testPrice{10, "usd"}, To: &testPrice{10, "eur"}},
+			}},
+		},
+		{
+			description: "map values",
+			from:        map[string]interface{}{"price": testMoney{1, "usd"}},
+			to:          map[string]interface{}{"price": testMoney{1, "USD"}},
+			expect:      &ChangeLog{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+}
@@ -9,12 +9,16 @@ import (
 
 type (
 	field struct {
-		name   string
-		from   accessor
-		to     accessor
-		Kind   reflect.Kind
-		tag    *Tag
-		differ *Differ
+		name          string
+		jsonName      string
+		jsonOmitEmpty bool
+		from          accessor
+		to            accessor
+		Kind          reflect.Kind
+		tag           *Tag
+		differ        *Differ
+		convert       bool  //from and to basic kinds differ, values are converted before comparison
+		mismatch      error //from and to kinds are reported as error in strict mode when values are not equal
 	}
 
 	matcher struct {
@@ -33,10 +37,19 @@ func newField(fromField *xunsafe.Field, fromAccessor accessor, toAccessor access
 	if tag.Name != "" {
 		aField.name = tag.Name
 	}
+	jsonTag := strings.Split(fromField.Tag.Get("json"), ",")
+	if jsonName := jsonTag[0]; jsonName != "" && jsonName != "-" {
+		aField.jsonName = jsonName
+	}
+	for _, option := range jsonTag[1:] {
+		aField.jsonOmitEmpty = aField.jsonOmitEmpty || option == "omitempty"
+	}
 	if structType(fromField.Type) != nil && !isTimeType(fromField.Type) {
 		aField.Kind = reflect.Struct
 	} else if sliceType(fromField.Type) != nil {
 		aField.Kind = reflect.Slice
+	} else if arrayType(fromField.Type) != nil {
+		aField.Kind = reflect.Array
 	} else if interfaceType(fromField.Type) != nil {
 		aField.Kind = reflect.Interface
 	} else if mapType(fromField.Type) != nil {
@@ -45,11 +58,19 @@ func newField(fromField *xunsafe.Field, fromAccessor accessor, toAccessor access
 	return aField
 }
 
+//path returns field path node
+func (f *field) path(parent *Path) *Path {
+	result := parent.Field(f.name)
+	result.jsonName = f.jsonName
+	result.jsonOmitEmpty = f.jsonOmitEmpty
+	return result
+}
+
 func (m *matcher) build(xStruct *xunsafe.Struct, config *Config) {
 	m.index = make(map[string]*accessor, 3*len(xStruct.Fields))
 	for i := range xStruct.Fields {
 		xField := &xStruct.Fields[i]
-		tag, _ := ParseTag(string(xField.Tag))
+		tag, _ := ParseTag(xField.Tag.Get(config.TagName))
 		tag.init(config)
 
 		tag.PresenceMarker = structology.IsSetMarker(xField.Tag)
@@ -57,6 +78,16 @@ func (m *matcher) build(xStruct *xunsafe.Struct, config *Config) {
 		m.index[xField.Name] = &fieldAccessor
 		m.index[strings.ToLower(xField.Name)] = &fieldAccessor
 		m.index[m.normKey(xField.Name)] = &fieldAccessor
+		for _, alias := range []string{tag.Name, strings.Split(xField.Tag.Get("json"), ",")[0]} {
+			if alias == "" || alias == "-" {
+				continue
+			}
+			for _, key := range []string{alias, strings.ToLower(alias), m.normKey(alias)} {
+				if _, ok := m.index[key]; !ok {
+					m.index[key] = &fieldAccessor
+				}
+			}
+		}
 	}
 }
 
@@ -3,6 +3,7 @@ module github.com/viant/godiff
 go 1.17
 
 require (
+	github.com/evanphx/json-patch/v5 v5.6.0
 	github.com/stretchr/testify v1.8.4
 	github.com/viant/parsly v0.1.0
 	github.com/viant/structology v0.1.0
@@ -11,6 +12,7 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/viant/xreflect v0.0.0-20230303201326-f50afb0feb0d // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
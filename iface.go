@@ -21,15 +21,21 @@ func (d *ifaceDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{
 	if from != nil {
 		fromValue = reflect.ValueOf(from)
 		fromStruct = structType(fromValue.Type())
+		if fromStruct != nil && d.config.isLeaf(fromStruct) {
+			fromStruct = nil
+		}
 	}
 
 	if to != nil {
 		toValue = reflect.ValueOf(to)
 		toStruct = structType(toValue.Type())
+		if toStruct != nil && d.config.isLeaf(toStruct) {
+			toStruct = nil
+		}
 	}
 
 	if fromStruct != nil && toStruct != nil {
-		differ, err := d.config.registry.Get(fromStruct, toStruct, d.tag)
+		differ, err := d.config.registry.Get(fromStruct, toStruct, d.tag, WithConfig(d.config))
 		if err != nil {
 			return err
 		}
@@ -43,7 +49,7 @@ func (d *ifaceDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{
 	}
 
 	if from == nil && toStruct != nil {
-		differ, err := d.config.registry.Get(toStruct, toStruct, d.tag)
+		differ, err := d.config.registry.Get(toStruct, toStruct, d.tag, WithConfig(d.config))
 		if err != nil {
 			return err
 		}
@@ -54,7 +60,7 @@ func (d *ifaceDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{
 	}
 
 	if to == nil && fromStruct != nil {
-		differ, err := d.config.registry.Get(fromStruct, fromStruct, d.tag)
+		differ, err := d.config.registry.Get(fromStruct, fromStruct, d.tag, WithConfig(d.config))
 		if err != nil {
 			return err
 		}
@@ -63,7 +69,35 @@ func (d *ifaceDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{
 		}
 		return differ.diff(changeLog, path, from, to, ChangeTypeDelete, options)
 	}
+	return d.diffValue(changeLog, path, from, to, options)
+}
 
+//diffValue compares non struct values, composite values of the same type are compared with registry differ
+func (d *ifaceDiffer) diffValue(changeLog *ChangeLog, path *Path, from, to interface{}, options *Options) error {
+	if from != nil && to != nil {
+		valueType := reflect.TypeOf(from)
+		if valueType == reflect.TypeOf(to) && !d.config.isLeaf(valueType) && (sliceType(valueType) != nil || arrayType(valueType) != nil || mapType(valueType) != nil) {
+			differ, err := d.config.registry.Get(valueType, valueType, d.tag, WithConfig(d.config))
+			if err != nil {
+				return err
+			}
+			return differ.diff(changeLog, path, from, to, ChangeTypeUpdate, options)
+		}
+	}
+	switch {
+	case from == nil:
+		changeLog.addValue(ChangeTypeCreate, path, nil, to, d.config.timeLayout(d.tag))
+	case to == nil:
+		changeLog.addValue(ChangeTypeDelete, path, from, nil, d.config.timeLayout(d.tag))
+	default:
+		matched, err := d.config.matches(from, to, d.tag)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			changeLog.addValue(ChangeTypeUpdate, path, from, to, d.config.timeLayout(d.tag))
+		}
+	}
 	return nil
 }
 
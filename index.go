@@ -4,43 +4,63 @@ import (
 	"fmt"
 	"github.com/viant/xunsafe"
 	"reflect"
+	"sort"
+	"strings"
 	"unsafe"
 )
 
 type entry struct {
+	key   interface{}
 	index int
 	value interface{}
 }
 
 type indexer struct {
-	field *xunsafe.Field
+	tagName string
 }
 
-func (i *indexer) indexBy(xSlice *xunsafe.Slice, ptr unsafe.Pointer, by string) map[interface{}]*entry {
-	if by == "" || by == "." {
-		return i.indexPrimitive(xSlice, ptr)
-	}
-	elemType := xSlice.Type.Elem()
-	if elemType.Kind() == reflect.Ptr {
-		elemType = elemType.Elem()
-	}
-	if elemType.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("%s not yet supported", elemType.String()))
+//compositeKey represents multi fields index key, i.e. indexBy=TenantID+SKU
+type compositeKey string
+
+//indexByFields returns index by field names, i.e. TenantID+SKU or TenantID|SKU
+func indexByFields(by string) []string {
+	return strings.FieldsFunc(by, func(r rune) bool {
+		return r == '+' || r == '|'
+	})
+}
+
+//newCompositeKey returns index key for field values, components are '+' separated with '+' and '\' escaped
+func newCompositeKey(values []interface{}) compositeKey {
+	builder := new(strings.Builder)
+	for i, value := range values {
+		if i > 0 {
+			builder.WriteByte('+')
+		}
+		builder.WriteString(keyEscaper.Replace(fmt.Sprintf("%v", value)))
 	}
-	i.field = xunsafe.FieldByName(elemType, by)
-	return i.indexByField(xSlice, ptr)
+	return compositeKey(builder.String())
 }
 
-func (i *indexer) indexByField(xSlice *xunsafe.Slice, ptr unsafe.Pointer) map[interface{}]*entry {
+var keyEscaper = strings.NewReplacer(`\`, `\\`, "+", `\+`)
+
+func (i *indexer) indexBy(xSlice *xunsafe.Slice, ptr unsafe.Pointer, by string) (map[interface{}]*entry, error) {
+	if ptr == nil {
+		return map[interface{}]*entry{}, nil //nil slice
+	}
+	if by == "" || by == "." {
+		return i.indexPrimitive(xSlice, ptr), nil
+	}
 	var result = make(map[interface{}]*entry)
 	l := xSlice.Len(ptr)
 	for j := 0; j < l; j++ {
 		value := xSlice.ValueAt(ptr, j)
-		ptr := xunsafe.AsPointer(value)
-		key := i.field.Value(ptr)
-		result[key] = &entry{index: j, value: value}
+		key, err := indexKey(reflect.ValueOf(value), by, i.tagName)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = &entry{key: key, index: j, value: value}
 	}
-	return result
+	return result, nil
 }
 
 func (i *indexer) indexPrimitive(xSlice *xunsafe.Slice, ptr unsafe.Pointer) map[interface{}]*entry {
@@ -48,7 +68,94 @@ func (i *indexer) indexPrimitive(xSlice *xunsafe.Slice, ptr unsafe.Pointer) map[
 	l := xSlice.Len(ptr)
 	for i := 0; i < l; i++ {
 		value := xSlice.ValueAt(ptr, i)
-		result[value] = &entry{index: i, value: value}
+		result[value] = &entry{key: value, index: i, value: value}
+	}
+	return result
+}
+
+//indexKey returns slice element index key, by defines '+' separated key fields, where field can be a dotted path
+//of struct fields (Go or diff tag name) or map keys, nil pointer or missing map entry on the path results in nil key component
+func indexKey(item reflect.Value, by string, tagName string) (interface{}, error) {
+	if by == "." {
+		item = indirect(item)
+		if !item.IsValid() {
+			return nil, nil
+		}
+		return item.Interface(), nil
+	}
+	names := indexByFields(by)
+	values := make([]interface{}, len(names))
+	for i, name := range names {
+		value, err := indexValue(item, name, tagName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid indexBy %v: %w", name, err)
+		}
+		values[i] = value
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return newCompositeKey(values), nil
+}
+
+//indexValue returns value at dotted path of struct fields or map keys
+func indexValue(item reflect.Value, name string, tagName string) (interface{}, error) {
+	value := item
+	for _, segment := range strings.Split(name, ".") {
+		value = indirect(value)
+		if !value.IsValid() {
+			return nil, nil
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			field, ok := indexField(value.Type(), segment, tagName)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %v in %s", segment, value.Type().String())
+			}
+			value = addressable(value)
+			value = reflect.NewAt(field.Type, unsafe.Pointer(value.Field(field.Index[0]).UnsafeAddr())).Elem()
+		case reflect.Map:
+			key, err := parseKey(segment, value.Type().Key())
+			if err != nil {
+				return nil, err
+			}
+			value = value.MapIndex(key)
+		default:
+			return nil, fmt.Errorf("unsupported %s element", value.Type().String())
+		}
+	}
+	value = indirect(value)
+	if !value.IsValid() {
+		return nil, nil
+	}
+	if !value.Type().Comparable() {
+		return nil, fmt.Errorf("%s key is not comparable", value.Type().String())
+	}
+	return value.Interface(), nil
+}
+
+//indexField returns struct field by Go or diff tag name
+func indexField(structType reflect.Type, name string, tagName string) (reflect.StructField, bool) {
+	if field, ok := structType.FieldByName(name); ok && len(field.Index) == 1 {
+		return field, true
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if tag, err := ParseTag(field.Tag.Get(tagName)); err == nil && tag.Name == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+//sortedEntries returns index entries sorted by element position
+func sortedEntries(index map[interface{}]*entry) []*entry {
+	result := make([]*entry, 0, len(index))
+	for _, item := range index {
+		result = append(result, item)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].index < result[j].index
+	})
 	return result
 }
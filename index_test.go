@@ -0,0 +1,261 @@
+package godiff
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_CompositeIndexBy(t *testing.T) {
+
+	type LineItem struct {
+		Warehouse string
+		SKU       string
+		Quantity  int
+	}
+	type Order struct {
+		ID    int
+		Items []LineItem `diff:"indexBy=Warehouse+SKU"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Order
+		to          *Order
+		expect      *ChangeLog
+	}{
+		{
+			description: "composite key match",
+			from:        &Order{Items: []LineItem{{"w1", "a", 1}, {"w2", "a", 2}, {"w1", "b", 3}}},
+			to:          &Order{Items: []LineItem{{"w2", "a", 5}, {"w1", "b", 3}, {"w2", "b", 4}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Items").KeyedElement("Warehouse+SKU", compositeKey("w2+a"), 1, 0).Field("Quantity"), From: 2, To: 5},
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Items").KeyedElement("Warehouse+SKU", compositeKey("w1+a"), 0, -1), From: LineItem{"w1", "a", 1}},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Items").KeyedElement("Warehouse+SKU", compositeKey("w2+b"), -1, 2), To: LineItem{"w2", "b", 4}},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Order{}), reflect.TypeOf(&Order{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		if !assert.EqualValues(t, testCase.expect, changeLog, testCase.description) {
+			continue
+		}
+		target := cloneValue(reflect.ValueOf(testCase.from)).Interface()
+		if assert.Nil(t, changeLog.Apply(target), testCase.description) {
+			assert.EqualValues(t, testCase.to, target, testCase.description)
+		}
+	}
+
+	assert.EqualValues(t, compositeKey(`w\+1+a\\b`), newCompositeKey([]interface{}{"w+1", `a\b`}))
+	assert.EqualValues(t, []string{"Warehouse", "SKU"}, indexByFields("Warehouse|SKU"))
+}
+
+func TestDiffer_NestedIndexBy(t *testing.T) {
+
+	type Meta struct {
+		ID   int `diff:"name=id"`
+		Kind string
+	}
+	type Item struct {
+		Meta  Meta
+		Value string
+	}
+	type Holder struct {
+		ID      int
+		Items   []*Item                  `diff:"indexBy=Meta.id"`
+		Records []map[string]interface{} `diff:"indexBy=id"`
+		Invalid []Item                   `diff:"indexBy=Meta.Unknown"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Holder
+		to          *Holder
+		expect      *ChangeLog
+	}{
+		{
+			description: "nested renamed field key",
+			from:        &Holder{Items: []*Item{{Meta{1, "a"}, "x"}, {Meta{2, "b"}, "y"}}},
+			to:          &Holder{Items: []*Item{{Meta{2, "b"}, "z"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Items").KeyedElement("Meta.id", 2, 1, 0).Field("Value"), From: "y", To: "z"},
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Items").KeyedElement("Meta.id", 1, 0, -1), From: &Item{Meta{1, "a"}, "x"}},
+			}},
+		},
+		{
+			description: "unknown key field",
+			from:        &Holder{Invalid: []Item{{Meta{1, "a"}, "x"}}},
+			to:          &Holder{Invalid: []Item{{Meta{1, "a"}, "y"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Path: (&Path{}).Field("Invalid"), Error: "invalid indexBy Meta.Unknown: unknown field Unknown in godiff.Meta"},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Holder{}), reflect.TypeOf(&Holder{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+
+	type Ref struct {
+		Code *string
+	}
+	code := "c1"
+	key, err := indexKey(reflect.ValueOf(&Ref{Code: &code}), "Code", "diff")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "c1", key)
+	key, err = indexKey(reflect.ValueOf(&Ref{}), "Code", "diff")
+	assert.Nil(t, err)
+	assert.Nil(t, key)
+	key, err = indexKey(reflect.ValueOf(map[string]interface{}{"id": 2, "v": "b"}), "id", "diff")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, key)
+	key, err = indexKey(reflect.ValueOf(map[string]interface{}{"v": "b"}), "id", "diff")
+	assert.Nil(t, err)
+	assert.Nil(t, key)
+}
+
+func TestDiffer_KeyedPath(t *testing.T) {
+
+	type Entry struct {
+		ID   int
+		Name string
+	}
+	type Holder struct {
+		ID      int
+		Entries []Entry `diff:"indexBy=ID"`
+	}
+
+	from := &Holder{Entries: []Entry{{42, "a"}, {7, "b"}, {9, "c"}}}
+	to := &Holder{Entries: []Entry{{9, "c"}, {42, "x"}, {11, "d"}}}
+	differ, err := New(reflect.TypeOf(&Holder{}), reflect.TypeOf(&Holder{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	changeLog := differ.Diff(from, to, WithMoveDetection(true))
+	var paths []string
+	for _, change := range changeLog.Changes {
+		paths = append(paths, string(change.Type)+" "+change.Path.String())
+	}
+	assert.EqualValues(t, []string{"update Entries[ID=42].Name", "delete Entries[ID=7]", "move Entries[ID=42]", "create Entries[ID=11]"}, paths)
+	update := changeLog.Changes[0].Path.Path
+	assert.EqualValues(t, 42, update.Key)
+	assert.EqualValues(t, 0, update.FromIndex)
+	assert.EqualValues(t, 1, update.ToIndex)
+
+	patched := &Holder{Entries: []Entry{{42, "a"}, {7, "b"}, {9, "c"}}}
+	if assert.Nil(t, changeLog.Apply(patched)) {
+		assert.EqualValues(t, to, patched)
+	}
+	inverted := changeLog.Invert()
+	assert.EqualValues(t, 1, inverted.Changes[3].Path.Path.FromIndex)
+	assert.EqualValues(t, 0, inverted.Changes[3].Path.Path.ToIndex)
+	if assert.Nil(t, inverted.Apply(patched)) {
+		assert.EqualValues(t, from, patched)
+	}
+
+	path, err := ParsePath("Entries[ID=9].Name")
+	if assert.Nil(t, err) {
+		value, err := path.Get(to)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "c", value)
+		assert.Nil(t, path.Set(patched, "z"))
+		assert.EqualValues(t, "z", patched.Entries[2].Name)
+	}
+	path, _ = ParsePath("Entries[ID=5]")
+	_, err = path.Get(to)
+	assert.NotNil(t, err)
+}
+
+func TestChangeLog_ApplyKeyedElements(t *testing.T) {
+
+	type Entry struct {
+		ID   int
+		Name string
+	}
+	type Holder struct {
+		ID      int
+		Entries []Entry `diff:"indexBy=ID"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Holder
+		to          *Holder
+		options     []Option
+		expect      []string
+		applied     *Holder
+		inverted    *Holder
+	}{
+		{
+			description: "reordered keyed update",
+			from:        &Holder{Entries: []Entry{{1, "a"}, {2, "b"}}},
+			to:          &Holder{Entries: []Entry{{2, "b"}, {1, "x"}}},
+			expect:      []string{"update Entries[ID=1].Name 0 1"},
+			applied:     &Holder{Entries: []Entry{{1, "x"}, {2, "b"}}},
+			inverted:    &Holder{Entries: []Entry{{2, "b"}, {1, "a"}}},
+		},
+		{
+			description: "reordered keyed update with moves",
+			from:        &Holder{Entries: []Entry{{1, "a"}, {2, "b"}}},
+			to:          &Holder{Entries: []Entry{{2, "b"}, {1, "x"}}},
+			options:     []Option{WithMoveDetection(true)},
+			expect:      []string{"update Entries[ID=1].Name 0 1", "move Entries[ID=1] 0 1"},
+			applied:     &Holder{Entries: []Entry{{2, "b"}, {1, "x"}}},
+			inverted:    &Holder{Entries: []Entry{{1, "a"}, {2, "b"}}},
+		},
+		{
+			description: "first element created",
+			from:        &Holder{},
+			to:          &Holder{Entries: []Entry{{42, "a"}}},
+			expect:      []string{"create Entries[ID=42] -1 0"},
+			applied:     &Holder{Entries: []Entry{{42, "a"}}},
+			inverted:    &Holder{},
+		},
+		{
+			description: "last element deleted",
+			from:        &Holder{Entries: []Entry{{42, "a"}}},
+			to:          &Holder{},
+			expect:      []string{"delete Entries[ID=42] 0 -1"},
+			applied:     &Holder{},
+			inverted:    &Holder{Entries: []Entry{{42, "a"}}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Holder{}), reflect.TypeOf(&Holder{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to, testCase.options...)
+		var actual []string
+		for _, change := range changeLog.Changes {
+			node := change.Path
+			for !node.IsKeyed() && node.Path != nil {
+				node = node.Path
+			}
+			actual = append(actual, fmt.Sprintf("%v %v %v %v", change.Type, change.Path.String(), node.FromIndex, node.ToIndex))
+		}
+		if !assert.EqualValues(t, testCase.expect, actual, testCase.description) {
+			continue
+		}
+		applied := cloneValue(reflect.ValueOf(testCase.from)).Interface()
+		if assert.Nil(t, changeLog.Apply(applied), testCase.description) {
+			assert.EqualValues(t, testCase.applied, applied, testCase.description)
+		}
+		inverted := cloneValue(reflect.ValueOf(testCase.to)).Interface()
+		if assert.Nil(t, changeLog.Invert().Apply(inverted), testCase.description) {
+			assert.EqualValues(t, testCase.inverted, inverted, testCase.description)
+		}
+	}
+}
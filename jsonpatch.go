@@ -0,0 +1,445 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+const (
+	//JSONPatchAdd defines RFC 6902 add operation
+	JSONPatchAdd = "add"
+	//JSONPatchRemove defines RFC 6902 remove operation
+	JSONPatchRemove = "remove"
+	//JSONPatchReplace defines RFC 6902 replace operation
+	JSONPatchReplace = "replace"
+	//JSONPatchMove defines RFC 6902 move operation
+	JSONPatchMove = "move"
+	//JSONPatchCopy defines RFC 6902 copy operation
+	JSONPatchCopy = "copy"
+	//JSONPatchTest defines RFC 6902 test operation
+	JSONPatchTest = "test"
+)
+
+//JSONPatchOperation represents RFC 6902 JSON patch operation
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+//JSONPatch returns RFC 6902 JSON patch document
+func (l *ChangeLog) JSONPatch() ([]byte, error) {
+	operations, err := l.JSONPatchOperations()
+	if err != nil {
+		return nil, err
+	}
+	if operations == nil {
+		operations = []*JSONPatchOperation{}
+	}
+	return json.Marshal(operations)
+}
+
+//JSONPatchOperations converts change log to RFC 6902 JSON patch operations, created or deleted container
+//(struct, slice, map or slice element) is added or removed with one operation, deleted struct field without
+//omitempty JSON option is replaced with null
+func (l *ChangeLog) JSONPatchOperations() ([]*JSONPatchOperation, error) {
+	var result []*JSONPatchOperation
+	containers := map[*Change]bool{}
+	for _, change := range l.sequenced() {
+		if change.Error != "" || change.Path == nil {
+			continue
+		}
+		if container := change.container; container != nil {
+			if containers[container] {
+				continue
+			}
+			containers[container] = true
+			change = container
+		}
+		operation := &JSONPatchOperation{Path: change.Path.JSONPointer()}
+		switch change.Type {
+		case ChangeTypeCreate:
+			operation.Op = JSONPatchAdd
+		case ChangeTypeDelete:
+			operation.Op = JSONPatchRemove
+			if change.Path.Kind == PathKinField && !change.Path.jsonOmitEmpty {
+				operation.Op = JSONPatchReplace
+			}
+		case ChangeTypeUpdate:
+			operation.Op = JSONPatchReplace
+		case ChangeTypeMove:
+			to, ok := change.To.(int)
+			if !ok || change.Path.Kind != PathKindIndex {
+				return nil, fmt.Errorf("invalid %v move: %v", change.Path.String(), change.To)
+			}
+			operation.Op = JSONPatchMove
+			operation.From = operation.Path
+			operation.Path = change.Path.at(to).JSONPointer()
+			result = append(result, operation)
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported change type: %v", change.Type)
+		}
+		if operation.Op != JSONPatchRemove {
+			value, err := json.Marshal(change.To)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %v value: %w", change.Path.String(), err)
+			}
+			operation.Value = value
+		}
+		result = append(result, operation)
+	}
+	return result, nil
+}
+
+//JSONPointer returns RFC 6901 JSON pointer, field nodes use JSON field name if available
+func (p *Path) JSONPointer() string {
+	builder := new(strings.Builder)
+	for _, node := range p.nodes() {
+		builder.WriteByte('/')
+		switch node.Kind {
+		case PathKinField:
+			name := node.Name
+			if node.jsonName != "" {
+				name = node.jsonName
+			}
+			builder.WriteString(escapeJSONPointer(name))
+		case PathKindKey:
+			builder.WriteString(escapeJSONPointer(fmt.Sprintf("%v", node.Key)))
+		case PathKindIndex:
+			builder.WriteString(strconv.Itoa(node.Index))
+		}
+	}
+	return builder.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapeJSONPointer(token string) string {
+	return jsonPointerEscaper.Replace(token)
+}
+
+type (
+	jsonPatchParser struct {
+		*patcher
+		root   reflect.Value //working copy the patch is applied to, invalid if only type is known
+		source reflect.Type
+	}
+
+	//jsonPatchTarget represents resolved JSON pointer
+	jsonPatchTarget struct {
+		path   *Path
+		xType  reflect.Type
+		value  reflect.Value
+		exists bool
+	}
+)
+
+//ParseJSONPatch parses RFC 6902 JSON patch document into a change log, JSON pointers are resolved against the source type
+//so that path nodes use field names (or diff tag names) rather than JSON names.
+//Source is either a reflect.Type or a value the patch applies to, with the value operations are evaluated in sequence on its copy,
+//so that change 'From' values are populated, 'move' and 'copy' are expanded to delete/create changes and 'test' is verified.
+func ParseJSONPatch(data []byte, source interface{}, opts ...ConfigOption) (*ChangeLog, error) {
+	var operations []*JSONPatchOperation
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	parser := &jsonPatchParser{patcher: newPatcher(newConfig(opts))}
+	switch actual := source.(type) {
+	case nil:
+		return nil, fmt.Errorf("JSON patch source was nil")
+	case reflect.Type:
+		parser.source = actual
+	default:
+		value := reflect.ValueOf(actual)
+		parser.root = reflect.New(value.Type())
+		parser.root.Elem().Set(cloneValue(value))
+		parser.source = value.Type()
+	}
+	changeLog := &ChangeLog{}
+	for i, operation := range operations {
+		if err := parser.parse(changeLog, operation); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON patch operation[%v] %v %v: %w", i, operation.Op, operation.Path, err)
+		}
+	}
+	return changeLog, nil
+}
+
+func (p *jsonPatchParser) parse(changeLog *ChangeLog, operation *JSONPatchOperation) error {
+	target, err := p.resolve(operation.Path)
+	if err != nil {
+		return err
+	}
+	switch operation.Op {
+	case JSONPatchAdd, JSONPatchReplace:
+		if operation.Value == nil {
+			return fmt.Errorf("value was missing")
+		}
+		value, err := decodeJSONValue(operation.Value, target.xType)
+		if err != nil {
+			return err
+		}
+		if operation.Op == JSONPatchReplace && p.root.IsValid() && !target.exists {
+			return fmt.Errorf("path does not exist")
+		}
+		return p.add(changeLog, target, value, operation.Op == JSONPatchReplace)
+	case JSONPatchRemove:
+		if p.root.IsValid() && !target.exists {
+			return fmt.Errorf("path does not exist")
+		}
+		return p.addChange(changeLog, &Change{Type: ChangeTypeDelete, Path: target.path, From: target.current()})
+	case JSONPatchTest:
+		if !p.root.IsValid() {
+			return fmt.Errorf("test operation requires source value")
+		}
+		if !target.exists {
+			return fmt.Errorf("path does not exist")
+		}
+		expected, err := normalizeJSONValue(operation.Value)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(target.value.Interface())
+		if err != nil {
+			return err
+		}
+		actual, err := normalizeJSONValue(data)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("test failed: expected %s, but had %s", operation.Value, data)
+		}
+		return nil
+	case JSONPatchMove, JSONPatchCopy:
+		if !p.root.IsValid() {
+			return fmt.Errorf("%v operation requires source value", operation.Op)
+		}
+		from, err := p.resolve(operation.From)
+		if err != nil {
+			return err
+		}
+		if !from.exists {
+			return fmt.Errorf("from path %v does not exist", operation.From)
+		}
+		data, err := json.Marshal(from.value.Interface())
+		if err != nil {
+			return err
+		}
+		if operation.Op == JSONPatchMove {
+			if strings.HasPrefix(operation.Path, operation.From+"/") {
+				return fmt.Errorf("unable to move %v to its child", operation.From)
+			}
+			if err = p.addChange(changeLog, &Change{Type: ChangeTypeDelete, Path: from.path, From: from.current()}); err != nil {
+				return err
+			}
+			if target, err = p.resolve(operation.Path); err != nil {
+				return err
+			}
+		}
+		value, err := decodeJSONValue(data, target.xType)
+		if err != nil {
+			return err
+		}
+		return p.add(changeLog, target, value, false)
+	}
+	return fmt.Errorf("unsupported operation: %v", operation.Op)
+}
+
+//add adds create change for new slice element or map entry, and update change otherwise
+func (p *jsonPatchParser) add(changeLog *ChangeLog, target *jsonPatchTarget, value interface{}, replace bool) error {
+	change := &Change{Type: ChangeTypeUpdate, Path: target.path, From: target.current(), To: value}
+	switch target.path.Kind {
+	case PathKindIndex:
+		if !replace {
+			change.Type = ChangeTypeCreate
+			change.From = nil
+		}
+	case PathKindKey:
+		if !target.exists {
+			change.Type = ChangeTypeCreate
+		}
+	}
+	return p.addChange(changeLog, change)
+}
+
+func (p *jsonPatchParser) addChange(changeLog *ChangeLog, change *Change) error {
+	if p.root.IsValid() {
+		if err := p.apply(p.root, change); err != nil {
+			return err
+		}
+	}
+	changeLog.Add(change)
+	return nil
+}
+
+//resolve resolves JSON pointer against source type (and value if available)
+func (p *jsonPatchParser) resolve(pointer string) (*jsonPatchTarget, error) {
+	if pointer != "" && pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer: %v", pointer)
+	}
+	result := &jsonPatchTarget{path: &Path{}, xType: p.source, exists: true}
+	if p.root.IsValid() {
+		result.value = p.root.Elem()
+	}
+	if pointer == "" {
+		return result, nil
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapeJSONPointer(token)
+		result.deref()
+		if !result.exists {
+			return nil, fmt.Errorf("parent of %v does not exist", token)
+		}
+		switch result.xType.Kind() {
+		case reflect.Struct:
+			xField, name, jsonName := p.jsonField(result.xType, token)
+			if xField == nil {
+				return nil, fmt.Errorf("unknown field %v in %s", token, result.xType.String())
+			}
+			result.path = result.path.Field(name)
+			result.path.jsonName = jsonName
+			result.xType = xField.Type
+			if result.value.IsValid() {
+				value := addressable(result.value)
+				result.value = reflect.NewAt(xField.Type, xField.Pointer(unsafe.Pointer(value.UnsafeAddr()))).Elem()
+			}
+		case reflect.Map:
+			key, err := parseKey(token, result.xType.Key())
+			if err != nil {
+				return nil, err
+			}
+			result.path = &Path{Kind: PathKindKey, Key: key.Interface(), Path: result.path}
+			result.xType = result.xType.Elem()
+			if result.value.IsValid() {
+				result.value = result.value.MapIndex(key)
+				result.exists = result.value.IsValid()
+			}
+		case reflect.Interface:
+			result.path = &Path{Kind: PathKindKey, Key: token, Path: result.path}
+			result.exists = !p.root.IsValid()
+		case reflect.Slice, reflect.Array:
+			index := 0
+			if token == "-" {
+				if !result.value.IsValid() {
+					return nil, fmt.Errorf("'-' index requires source value")
+				}
+				index = result.value.Len()
+			} else {
+				var err error
+				if index, err = strconv.Atoi(token); err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index: %v", token)
+				}
+			}
+			result.path = result.path.Element(index)
+			result.xType = result.xType.Elem()
+			if result.value.IsValid() {
+				if result.exists = index < result.value.Len(); result.exists {
+					result.value = result.value.Index(index)
+				} else {
+					result.value = reflect.Value{}
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unable to resolve %v in %s", token, result.xType.String())
+		}
+	}
+	return result, nil
+}
+
+//jsonField returns struct field matching JSON pointer token with its diff name and json name, only fields exposed in JSON
+//are matched: exported fields that are not excluded with json:"-", by exact json tag name, or by field name if tag does not define one
+func (p *jsonPatchParser) jsonField(structType reflect.Type, token string) (*xunsafe.Field, string, string) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue //unexported
+		}
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		jsonName := strings.Split(jsonTag, ",")[0]
+		if jsonName != token && (jsonName != "" || field.Name != token) {
+			continue
+		}
+		name := field.Name
+		if tag, err := ParseTag(field.Tag.Get(p.config.TagName)); err == nil && tag.Name != "" {
+			name = tag.Name
+		}
+		return xunsafe.FieldByIndex(structType, i), name, jsonName
+	}
+	return nil, "", ""
+}
+
+//deref dereferences pointer and interface target
+func (t *jsonPatchTarget) deref() {
+	for {
+		switch t.xType.Kind() {
+		case reflect.Ptr:
+			t.xType = t.xType.Elem()
+			if t.value.IsValid() {
+				if t.value.IsNil() {
+					t.value, t.exists = reflect.Value{}, false
+					continue
+				}
+				t.value = t.value.Elem()
+			}
+			continue
+		case reflect.Interface:
+			if t.value.IsValid() && !t.value.IsNil() {
+				t.value = t.value.Elem()
+				t.xType = t.value.Type()
+				continue
+			}
+		}
+		return
+	}
+}
+
+//current returns current target value
+func (t *jsonPatchTarget) current() interface{} {
+	if !t.exists || !t.value.IsValid() {
+		return nil
+	}
+	value := t.value
+	if value.Kind() == reflect.Ptr && !value.IsNil() && structType(value.Type()) == nil {
+		value = value.Elem()
+	}
+	return value.Interface()
+}
+
+func decodeJSONValue(data []byte, target reflect.Type) (interface{}, error) {
+	value := reflect.New(target)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("invalid value %s for %s: %w", data, target.String(), err)
+	}
+	result := value.Elem()
+	if result.Kind() == reflect.Ptr {
+		if result.IsNil() {
+			return nil, nil
+		}
+		if structType(result.Type()) == nil {
+			result = result.Elem()
+		}
+	}
+	return result.Interface(), nil
+}
+
+func normalizeJSONValue(data []byte) (interface{}, error) {
+	var result interface{}
+	err := json.Unmarshal(data, &result)
+	return result, err
+}
+
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func unescapeJSONPointer(token string) string {
+	return jsonPointerUnescaper.Replace(token)
+}
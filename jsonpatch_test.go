@@ -0,0 +1,234 @@
+package godiff
+
+import (
+	"encoding/json"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestChangeLog_JSONPatch(t *testing.T) {
+
+	type Item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+
+	type Record struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Desc  string
+		Nums  []int   `json:"nums"`
+		Items []*Item `json:"items"`
+		Dep   *Item   `json:"dep,omitempty"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		expect      string
+	}{
+		{
+			description: "json field names",
+			from:        &Record{ID: 1, Name: "abc", Desc: "desc"},
+			to:          &Record{ID: 2, Name: "xyz", Desc: "new desc"},
+			expect:      `[{"op":"replace","path":"/id","value":2},{"op":"replace","path":"/name","value":"xyz"},{"op":"replace","path":"/Desc","value":"new desc"}]`,
+		},
+		{
+			description: "slice elements",
+			from:        &Record{Nums: []int{1, 2, 3}, Items: []*Item{{ID: 1}}},
+			to:          &Record{Nums: []int{4}, Items: []*Item{{ID: 1}, {ID: 2, Name: "b"}}},
+			expect:      `[{"op":"replace","path":"/nums/0","value":4},{"op":"remove","path":"/nums/2"},{"op":"remove","path":"/nums/1"},{"op":"add","path":"/items/1","value":{"id":2,"name":"b"}}]`,
+		},
+		{
+			description: "new nested pointer",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1, Dep: &Item{Name: "a"}},
+			expect:      `[{"op":"add","path":"/dep","value":{"id":0,"name":"a"}}]`,
+		},
+		{
+			description: "removed nested pointer",
+			from:        &Record{ID: 1, Dep: &Item{ID: 2, Name: "a"}},
+			to:          &Record{ID: 1},
+			expect:      `[{"op":"remove","path":"/dep"}]`,
+		},
+		{
+			description: "removed slice element",
+			from:        &Record{Items: []*Item{{ID: 1}, {ID: 2, Name: "b"}}},
+			to:          &Record{Items: []*Item{{ID: 1}}},
+			expect:      `[{"op":"remove","path":"/items/1"}]`,
+		},
+		{
+			description: "new slice",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1, Items: []*Item{{ID: 2}}},
+			expect:      `[{"op":"add","path":"/items","value":[{"id":2}]}]`,
+		},
+		{
+			description: "removed slice",
+			from:        &Record{ID: 1, Items: []*Item{{ID: 2}}},
+			to:          &Record{ID: 1},
+			expect:      `[{"op":"replace","path":"/items","value":null}]`,
+		},
+		{
+			description: "new nested map",
+			from:        map[string]interface{}{"a": []interface{}{}},
+			to:          map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1, "c": 2}}},
+			expect:      `[{"op":"add","path":"/a/0","value":{"b":1,"c":2}}]`,
+		},
+		{
+			description: "map entry update",
+			from:        map[string]interface{}{"a/b": 1},
+			to:          map[string]interface{}{"a/b": 3},
+			expect:      `[{"op":"replace","path":"/a~1b","value":3}]`,
+		},
+		{
+			description: "map entry remove",
+			from:        map[string]interface{}{"a/b": 1, "c~d": 2},
+			to:          map[string]interface{}{"a/b": 1},
+			expect:      `[{"op":"remove","path":"/c~0d"}]`,
+		},
+		{
+			description: "no changes",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1},
+			expect:      `[]`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		actual, err := changeLog.JSONPatch()
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, string(actual), testCase.description)
+		patch, err := jsonpatch.DecodePatch(actual)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		from, _ := json.Marshal(testCase.from)
+		to, _ := json.Marshal(testCase.to)
+		patched, err := patch.Apply(from)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.JSONEq(t, string(to), string(patched), testCase.description)
+	}
+}
+
+func TestParseJSONPatch(t *testing.T) {
+
+	type Item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+
+	type Record struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name" diff:"name=title"`
+		Desc  string
+		Nums  []int   `json:"nums"`
+		Items []*Item `json:"items"`
+		Hash  string  `json:"-"`
+		role  string
+	}
+
+	var testCases = []struct {
+		description string
+		source      interface{}
+		patch       string
+		expectPaths []string
+		expect      interface{}
+		expectErr   bool
+	}{
+		{
+			description: "add, replace, remove",
+			source:      &Record{ID: 1, Name: "abc", Desc: "desc", Nums: []int{1, 2}},
+			patch:       `[{"op":"replace","path":"/id","value":2},{"op":"add","path":"/nums/0","value":0},{"op":"remove","path":"/nums/2"},{"op":"add","path":"/nums/-","value":3},{"op":"replace","path":"/name","value":"xyz"},{"op":"remove","path":"/Desc"}]`,
+			expectPaths: []string{"update:ID", "create:Nums[0]", "delete:Nums[2]", "create:Nums[2]", "update:title", "delete:Desc"},
+			expect:      &Record{ID: 2, Name: "xyz", Nums: []int{0, 1, 3}},
+		},
+		{
+			description: "move, copy, test",
+			source:      &Record{ID: 1, Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}},
+			patch:       `[{"op":"test","path":"/items/1/name","value":"b"},{"op":"move","from":"/items/1","path":"/items/0"},{"op":"copy","from":"/items/1/name","path":"/Desc"}]`,
+			expectPaths: []string{"delete:Items[1]", "create:Items[0]", "update:Desc"},
+			expect:      &Record{ID: 1, Desc: "a", Items: []*Item{{ID: 2, Name: "b"}, {ID: 1, Name: "a"}}},
+		},
+		{
+			description: "failed test",
+			source:      &Record{ID: 1},
+			patch:       `[{"op":"test","path":"/id","value":2}]`,
+			expectErr:   true,
+		},
+		{
+			description: "unknown field",
+			source:      reflect.TypeOf(&Record{}),
+			patch:       `[{"op":"replace","path":"/unknown","value":2}]`,
+			expectErr:   true,
+		},
+		{
+			description: "json excluded field",
+			source:      &Record{ID: 1, Hash: "abc"},
+			patch:       `[{"op":"replace","path":"/Hash","value":"xyz"}]`,
+			expectErr:   true,
+		},
+		{
+			description: "unexported field",
+			source:      &Record{ID: 1, role: "user"},
+			patch:       `[{"op":"replace","path":"/role","value":"admin"}]`,
+			expectErr:   true,
+		},
+		{
+			description: "case insensitive field name",
+			source:      &Record{ID: 1},
+			patch:       `[{"op":"replace","path":"/ID","value":2}]`,
+			expectErr:   true,
+		},
+		{
+			description: "type source",
+			source:      reflect.TypeOf(&Record{}),
+			patch:       `[{"op":"replace","path":"/items/1/id","value":2},{"op":"add","path":"/nums/0","value":1}]`,
+			expectPaths: []string{"update:Items[1].ID", "create:Nums[0]"},
+		},
+		{
+			description: "map source",
+			source:      map[string]interface{}{"k1": "v1", "k/2": []interface{}{1.0}},
+			patch:       `[{"op":"add","path":"/k3","value":"v3"},{"op":"replace","path":"/k1","value":"v1.1"},{"op":"add","path":"/k~12/1","value":2}]`,
+			expectPaths: []string{"create:[k3]", "update:[k1]", "create:[k/2][1]"},
+			expect:      map[string]interface{}{"k1": "v1.1", "k/2": []interface{}{1.0, 2.0}, "k3": "v3"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		changeLog, err := ParseJSONPatch([]byte(testCase.patch), testCase.source)
+		if testCase.expectErr {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actualPaths []string
+		for _, change := range changeLog.Changes {
+			actualPaths = append(actualPaths, string(change.Type)+":"+change.Path.String())
+		}
+		assert.EqualValues(t, testCase.expectPaths, actualPaths, testCase.description)
+		if testCase.expect == nil {
+			continue
+		}
+		target := reflect.New(reflect.TypeOf(testCase.source))
+		target.Elem().Set(reflect.ValueOf(testCase.source))
+		if !assert.Nil(t, changeLog.Apply(target.Interface()), testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, target.Elem().Interface(), testCase.description)
+	}
+}
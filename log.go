@@ -2,6 +2,8 @@ package godiff
 
 import (
 	"encoding/json"
+	"reflect"
+	"sort"
 )
 
 type (
@@ -31,17 +33,164 @@ func (l *ChangeLog) AddError(path *Path, err error) {
 
 //AddCreate adds create change
 func (l *ChangeLog) AddCreate(path *Path, value interface{}) {
-	l.Add(&Change{Type: ChangeTypeCreate, Path: path, To: value})
+	l.Add(&Change{Type: ChangeTypeCreate, Path: path, To: cloneInterface(value)})
 }
 
 //AddDelete adds delete change
 func (l *ChangeLog) AddDelete(path *Path, value interface{}) {
-	l.Add(&Change{Type: ChangeTypeDelete, Path: path, From: value})
+	l.Add(&Change{Type: ChangeTypeDelete, Path: path, From: cloneInterface(value)})
 }
 
 //AddUpdate adds update change
 func (l *ChangeLog) AddUpdate(path *Path, from, to interface{}) {
-	l.Add(&Change{Type: ChangeTypeUpdate, Path: path, From: from, To: to})
+	l.Add(&Change{Type: ChangeTypeUpdate, Path: path, From: cloneInterface(from), To: cloneInterface(to)})
+}
+
+//AddMove adds slice element move change, from and to are element positions in from and to slices
+func (l *ChangeLog) AddMove(path *Path, from, to int) {
+	l.Add(&Change{Type: ChangeTypeMove, Path: path.Element(from), From: from, To: to})
+}
+
+//addValue adds leaf value change, time values are rendered with time layout in change records
+func (l *ChangeLog) addValue(changeType ChangeType, path *Path, from, to interface{}, timeLayout string) {
+	change := &Change{Type: changeType, Path: path, From: cloneInterface(from), To: cloneInterface(to)}
+	if _, ok := asTime(from); ok {
+		change.timeLayout = timeLayout
+	} else if _, ok = asTime(to); ok {
+		change.timeLayout = timeLayout
+	}
+	l.Add(change)
+}
+
+//sequenced returns changes where slice element moves use positions at the time the move is applied in sequence,
+//that is after removed elements are deleted and before added elements are created
+func (l *ChangeLog) sequenced() []*Change {
+	moved := map[string][]*Change{}
+	for _, change := range l.Changes {
+		if change.Type == ChangeTypeMove && change.Error == "" && isElement(change.Path) {
+			parent := change.Path.Path.String()
+			moved[parent] = append(moved[parent], change)
+		}
+	}
+	if len(moved) == 0 {
+		return l.Changes
+	}
+	deleted := map[string]map[int]bool{}
+	created := map[string]map[int]bool{}
+	for _, change := range l.Changes {
+		if change.Error != "" || !isElement(change.Path) {
+			continue
+		}
+		parent := change.Path.Path.String()
+		if _, ok := moved[parent]; !ok {
+			continue
+		}
+		switch change.Type {
+		case ChangeTypeDelete:
+			addIndex(deleted, parent, change.Path.Index)
+		case ChangeTypeCreate:
+			addIndex(created, parent, change.Path.Index)
+		}
+	}
+	//moves of the same slice take their log places in sequence order
+	sequenced := map[*Change]*Change{}
+	for parent, changes := range moved {
+		var valid []*Change
+		var pairs [][2]int
+		for _, change := range changes {
+			from, fromOk := moveIndex(change.From)
+			to, toOk := moveIndex(change.To)
+			if !fromOk || !toOk {
+				continue //invalid move is reported when applied
+			}
+			valid = append(valid, change)
+			pairs = append(pairs, [2]int{from, to})
+		}
+		for i, move := range movePositions(deleted[parent], created[parent], pairs) {
+			change := valid[move[0]]
+			sequenced[valid[i]] = &Change{Type: ChangeTypeMove, Path: change.Path.at(move[1]), From: move[1], To: move[2]}
+		}
+	}
+	result := make([]*Change, len(l.Changes))
+	for i, change := range l.Changes {
+		if actual, ok := sequenced[change]; ok {
+			change = actual
+		}
+		result[i] = change
+	}
+	return result
+}
+
+//isElement returns true if path is slice element node
+func isElement(path *Path) bool {
+	return path != nil && path.Kind == PathKindIndex && path.Path != nil
+}
+
+func addIndex(indexes map[string]map[int]bool, parent string, index int) {
+	if indexes[parent] == nil {
+		indexes[parent] = map[int]bool{}
+	}
+	indexes[parent][index] = true
+}
+
+//moveIndex returns move change position
+func moveIndex(value interface{}) (int, bool) {
+	index, err := assignableValue(value, reflect.TypeOf(0))
+	if err != nil {
+		return 0, false
+	}
+	return int(index.Int()), true
+}
+
+//setContainer links changes added from offset with the whole container create or delete change,
+//nested containers are linked with the outermost one
+func (l *ChangeLog) setContainer(offset int, changeType ChangeType, path *Path, from, to interface{}) {
+	if offset >= len(l.Changes) {
+		return
+	}
+	container := &Change{Type: changeType, Path: path}
+	switch {
+	case changeType == ChangeTypeCreate && from == nil && to != nil:
+		container.To = cloneInterface(to)
+	case changeType == ChangeTypeDelete && to == nil && from != nil:
+		container.From = cloneInterface(from)
+	default:
+		return
+	}
+	for _, change := range l.Changes[offset:] {
+		change.container = container
+	}
+}
+
+//Sort sorts changes by path: fields by name, map entries by key, slice elements by key (indexBy) or position,
+//ancestor changes go before descendant ones, and changes with the same path keep their order,
+//note that sorted change log is meant for presentation, since Apply relies on the original changes order
+func (l *ChangeLog) Sort() {
+	sort.SliceStable(l.Changes, func(i, j int) bool {
+		return l.Changes[i].Path.compare(l.Changes[j].Path) < 0
+	})
+}
+
+//Invert returns a change log undoing the original change log, changes are in reversed order,
+//creates become deletes, deletes become creates and updates have From/To swapped
+func (l *ChangeLog) Invert() *ChangeLog {
+	result := &ChangeLog{Changes: make([]*Change, 0, len(l.Changes))}
+	containers := map[*Change]*Change{}
+	for i := len(l.Changes) - 1; i >= 0; i-- {
+		change := l.Changes[i]
+		if change.Error != "" {
+			continue
+		}
+		inverted := change.invert()
+		if change.container != nil {
+			if _, ok := containers[change.container]; !ok {
+				containers[change.container] = change.container.invert()
+			}
+			inverted.container = containers[change.container]
+		}
+		result.Add(inverted)
+	}
+	return result
 }
 
 //ToChangeRecords converts changeLog to change records
@@ -54,13 +203,26 @@ func (l *ChangeLog) ToChangeRecords(source, id, userID string) []*ChangeRecord {
 			UserID:   userID,
 			Path:     change.Path.String(),
 			Change:   string(change.Type),
-			From:     change.From,
-			To:       change.To,
+			From:     formatTime(change.From, change.timeLayout),
+			To:       formatTime(change.To, change.timeLayout),
 		})
 	}
 	return result
 }
 
+//FromChangeRecords converts change records back to change log
+func FromChangeRecords(records []*ChangeRecord) (*ChangeLog, error) {
+	result := &ChangeLog{Changes: make([]*Change, 0, len(records))}
+	for _, record := range records {
+		path, err := ParsePath(record.Path)
+		if err != nil {
+			return nil, err
+		}
+		result.Add(&Change{Type: ChangeType(record.Change), Path: path, From: record.From, To: record.To, Error: record.Error})
+	}
+	return result, nil
+}
+
 //String stringify change
 func (l *ChangeLog) String() string {
 	if len(l.Changes) == 0 {
@@ -0,0 +1,196 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestChangeLog_Invert(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+
+	type Record struct {
+		ID    int
+		Name  string
+		Nums  []int
+		Items []*Item
+		Codes []string `diff:"indexBy=."`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Record
+		to          *Record
+	}{
+		{
+			description: "basic fields",
+			from:        &Record{ID: 1, Name: "abc"},
+			to:          &Record{ID: 2, Name: "xyz"},
+		},
+		{
+			description: "slice elements removed",
+			from:        &Record{Nums: []int{1, 2, 3, 4}},
+			to:          &Record{Nums: []int{1, 5}},
+		},
+		{
+			description: "slice elements added",
+			from:        &Record{Nums: []int{1}, Items: []*Item{{ID: 1, Name: "a"}}},
+			to:          &Record{Nums: []int{1, 2, 3}, Items: []*Item{{ID: 1, Name: "b"}, {ID: 2, Name: "c"}}},
+		},
+		{
+			description: "indexed slice elements",
+			from:        &Record{Codes: []string{"a", "b", "c"}},
+			to:          &Record{Codes: []string{"x", "a", "c", "y"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		target := *testCase.to
+		if target.Nums != nil {
+			target.Nums = append([]int{}, testCase.to.Nums...)
+		}
+		if target.Codes != nil {
+			target.Codes = append([]string{}, testCase.to.Codes...)
+		}
+		if !assert.Nil(t, differ.Patch(&target, changeLog.Invert()), testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.from.Nums, target.Nums, testCase.description)
+		assert.EqualValues(t, testCase.from.Codes, target.Codes, testCase.description)
+		assert.EqualValues(t, testCase.from.Items, target.Items, testCase.description)
+		assert.EqualValues(t, testCase.from.ID, target.ID, testCase.description)
+		assert.EqualValues(t, testCase.from.Name, target.Name, testCase.description)
+		assert.EqualValues(t, differ.Diff(testCase.from, &target).Size(), 0, testCase.description)
+	}
+}
+
+func TestChangeLog_Sort(t *testing.T) {
+	root := &Path{}
+	changeLog := &ChangeLog{Changes: []*Change{
+		{Type: ChangeTypeUpdate, Path: root.Field("Name")},
+		{Type: ChangeTypeCreate, Path: root.Field("Items").KeyedElement("ID", 7, -1, 0)},
+		{Type: ChangeTypeUpdate, Path: root.Field("Attrs").Entry("b")},
+		{Type: ChangeTypeDelete, Path: root.Field("Flags").Element(2)},
+		{Type: ChangeTypeUpdate, Path: root.Field("Items").KeyedElement("ID", 3, 1, 1).Field("Name")},
+		{Type: ChangeTypeUpdate, Path: root.Field("Attrs").Entry("a")},
+		{Type: ChangeTypeDelete, Path: root.Field("Flags").Element(0)},
+		{Type: ChangeTypeUpdate, Path: root.Field("Attrs")},
+	}}
+	changeLog.Sort()
+	var actual []string
+	for _, change := range changeLog.Changes {
+		actual = append(actual, change.Path.String())
+	}
+	assert.EqualValues(t, []string{"Attrs", "Attrs[a]", "Attrs[b]", "Flags[0]", "Flags[2]", "Items[ID=3].Name", "Items[ID=7]", "Name"}, actual)
+}
+
+func TestDiffer_DeterministicOrder(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+	type Holder struct {
+		ID    int
+		Items []Item `diff:"indexBy=ID"`
+		Attrs interface{}
+	}
+	from := &Holder{Attrs: map[string]interface{}{}}
+	to := &Holder{Attrs: map[string]interface{}{}}
+	for i := 0; i < 50; i++ {
+		from.Items = append(from.Items, Item{i, "a"})
+		to.Items = append(to.Items, Item{i + 25, "b"})
+		from.Attrs.(map[string]interface{})[fmt.Sprintf("k%v", i)] = i
+		to.Attrs.(map[string]interface{})[fmt.Sprintf("k%v", i+25)] = i + 1
+	}
+	differ, err := New(reflect.TypeOf(from), reflect.TypeOf(to))
+	if !assert.Nil(t, err) {
+		return
+	}
+	expect, err := json.Marshal(differ.Diff(from, to))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for i := 0; i < 20; i++ {
+		actual, err := json.Marshal(differ.Diff(from, to))
+		assert.Nil(t, err)
+		assert.EqualValues(t, string(expect), string(actual))
+	}
+}
+
+func TestChangeLog_Invert_AfterApply(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+
+	type Record struct {
+		ID    int
+		Dep   Item
+		Nums  []int
+		Items []*Item
+		Codes []string `diff:"indexBy=."`
+	}
+
+	var testCases = []struct {
+		description string
+		from        func() interface{}
+		to          func() interface{}
+	}{
+		{
+			description: "nested fields",
+			from:        func() interface{} { return &Record{ID: 1, Dep: Item{ID: 3, Name: "a"}} },
+			to:          func() interface{} { return &Record{ID: 2, Dep: Item{ID: 4, Name: "b"}} },
+		},
+		{
+			description: "slice elements",
+			from:        func() interface{} { return &Record{Nums: []int{1, 2, 3}, Items: []*Item{{ID: 1, Name: "a"}}} },
+			to: func() interface{} {
+				return &Record{Nums: []int{4}, Items: []*Item{{ID: 1, Name: "b"}, {ID: 2, Name: "c"}}}
+			},
+		},
+		{
+			description: "map entries",
+			from: func() interface{} {
+				return &map[string]interface{}{"k1": "v1", "k2": map[string]interface{}{"a": 1}, "k3": []interface{}{1, 2}}
+			},
+			to: func() interface{} {
+				return &map[string]interface{}{"k1": "v2", "k2": map[string]interface{}{"a": 2, "b": 3}, "k3": []interface{}{3}}
+			},
+		},
+		{
+			description: "indexed slice elements",
+			from:        func() interface{} { return &Record{Codes: []string{"a", "b", "c"}} },
+			to:          func() interface{} { return &Record{Codes: []string{"x", "a", "c", "y"}} },
+		},
+	}
+
+	for _, testCase := range testCases {
+		from, to := testCase.from(), testCase.to()
+		differ, err := New(reflect.TypeOf(from), reflect.TypeOf(to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(from, to)
+		if !assert.Nil(t, changeLog.Apply(from), testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, to, from, testCase.description)
+		if !assert.Nil(t, changeLog.Invert().Apply(from), testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.from(), from, testCase.description)
+	}
+}
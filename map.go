@@ -3,105 +3,152 @@ package godiff
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 )
 
 type mapDiffer struct {
-	config        *Config
-	itemDiffer    *Differ
-	isInterface   bool
-	tag           *Tag
-	from, to      reflect.Type
-	isStringIface bool
+	config      *Config
+	itemDiffer  *Differ
+	itemErr     error
+	itemOnce    sync.Once
+	isInterface bool
+	isSlice     bool
+	tag         *Tag
+	from, to    reflect.Type
 }
 
 func (s *mapDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{}, changeType ChangeType, options *Options) error {
 	if from == nil && to == nil {
 		return nil
 	}
-	if !s.isStringIface {
-		return fmt.Errorf("type: %T not supported yet", from)
+	fromMap := indirect(reflect.ValueOf(from))
+	toMap := indirect(reflect.ValueOf(to))
+	if fromMap.IsValid() && fromMap.Kind() != reflect.Map {
+		return fmt.Errorf("invalid from type: %T", from)
 	}
-	var ok bool
-	var fromMap, toMap map[string]interface{}
-	if from != nil {
-		if fromMap, ok = from.(map[string]interface{}); !ok {
-			return fmt.Errorf("invalid from type: %T", from)
-		}
+	if toMap.IsValid() && toMap.Kind() != reflect.Map {
+		return fmt.Errorf("invalid to type: %T", to)
 	}
-	if to != nil {
-		if toMap, ok = to.(map[string]interface{}); !ok {
-			return fmt.Errorf("invalid to type: %T", from)
-		}
+	itemDiffer, err := s.getItemDiffer()
+	if err != nil {
+		return err
 	}
-	var err error
-
-	if from == nil {
-		for k, v := range toMap {
-			if err = s.diffIfaceElement(changeLog, path, nil, v, k, ChangeTypeDelete, options); err != nil {
-				return err
-			}
+	for _, key := range sortedKeys(fromMap) {
+		var toItem reflect.Value
+		if toKey, ok := convertKey(key, s.to.Key()); ok && toMap.IsValid() {
+			toItem = toMap.MapIndex(toKey)
 		}
-	} else if to == nil {
-		for k, v := range fromMap {
-			if err = s.diffIfaceElement(changeLog, path, v, nil, k, ChangeTypeCreate, options); err != nil {
-				return err
-			}
+		if err = s.diffEntry(changeLog, path.EntryKey(key.Interface()), itemDiffer, fromMap.MapIndex(key), toItem, options); err != nil {
+			return err
 		}
-	} else {
-
-		for k, fromItem := range fromMap {
-			toItem := toMap[k]
-			if err = s.diffIfaceElement(changeLog, path, fromItem, toItem, k, ChangeTypeCreate, options); err != nil {
-				return err
-			}
+	}
+	for _, key := range sortedKeys(toMap) {
+		if fromKey, ok := convertKey(key, s.from.Key()); ok && fromMap.IsValid() && fromMap.MapIndex(fromKey).IsValid() {
+			continue
 		}
-
-		for k, toItem := range toMap {
-			if _, has := fromMap[k]; has {
-				continue
-			}
-			if err = s.diffIfaceElement(changeLog, path, nil, toItem, k, ChangeTypeCreate, options); err != nil {
-				return err
-			}
+		if err = s.diffEntry(changeLog, path.EntryKey(key.Interface()), itemDiffer, reflect.Value{}, toMap.MapIndex(key), options); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (s *mapDiffer) diffIfaceElement(changeLog *ChangeLog, path *Path, from, to interface{}, key string, changeType ChangeType, options *Options) error {
-	var fromValue, toValue reflect.Value
+func (s *mapDiffer) diffEntry(changeLog *ChangeLog, path *Path, itemDiffer *Differ, fromItem, toItem reflect.Value, options *Options) error {
+	from := s.entryValue(fromItem)
+	to := s.entryValue(toItem)
 	if from == nil && to == nil {
 		return nil
 	}
+	return itemDiffer.diff(changeLog, path, from, to, discoverChangeType(from, to), options)
+}
 
-	if to != nil {
-		toValue = reflect.ValueOf(to)
-		fromValue = toValue
-	} else if from != nil {
-		fromValue = reflect.ValueOf(from)
-		toValue = fromValue
-	} else {
-		toValue = reflect.ValueOf(to)
-		fromValue = reflect.ValueOf(from)
+//entryValue returns map entry value, nil for missing entry or nil value, slices are passed by pointer to slice differ
+func (s *mapDiffer) entryValue(item reflect.Value) interface{} {
+	if !item.IsValid() {
+		return nil
+	}
+	switch item.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		if item.IsNil() {
+			return nil
+		}
+	case reflect.Slice:
+		if item.IsNil() {
+			return nil
+		}
+		if s.isSlice {
+			ptr := reflect.New(item.Type())
+			ptr.Elem().Set(item)
+			return ptr.Interface()
+		}
 	}
+	return item.Interface()
+}
+
+//getItemDiffer returns map value differ, typed differs are resolved lazily (once, differ can be shared by goroutines) with registry,
+//so that recursive types are supported
+func (s *mapDiffer) getItemDiffer() (*Differ, error) {
+	s.itemOnce.Do(func() {
+		if s.isInterface {
+			differ, _ := newIfaceDiffer(s.config, s.tag)
+			s.itemDiffer = &Differ{config: s.config, ifaceDiffer: differ}
+			return
+		}
+		s.itemDiffer, s.itemErr = s.config.registry.Get(s.from.Elem(), s.to.Elem(), s.tag, WithConfig(s.config))
+	})
+	return s.itemDiffer, s.itemErr
+}
 
-	if fromValue.Kind() == reflect.Ptr {
-		fromValue = fromValue.Elem()
-		from = fromValue.Interface()
+//convertKey converts map key to the other map key type
+func convertKey(key reflect.Value, keyType reflect.Type) (reflect.Value, bool) {
+	if key.Type() == keyType {
+		return key, true
 	}
-	if toValue.Kind() == reflect.Ptr {
-		toValue = toValue.Elem()
-		to = toValue.Elem()
+	if key.Type().ConvertibleTo(keyType) {
+		return key.Convert(keyType), true
 	}
+	return reflect.Value{}, false
+}
 
-	itemDiffer, err := s.config.registry.Get(fromValue.Type(), toValue.Type(), s.tag)
-	if err != nil {
-		return err
+//sortedKeys returns map keys in ascending order
+func sortedKeys(aMap reflect.Value) []reflect.Value {
+	if !aMap.IsValid() || aMap.Len() == 0 {
+		return nil
+	}
+	keys := aMap.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+	return keys
+}
+
+func lessKey(x, y reflect.Value) bool {
+	switch x.Kind() {
+	case reflect.String:
+		return x.String() < y.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() < y.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return x.Uint() < y.Uint()
+	case reflect.Float32, reflect.Float64:
+		return x.Float() < y.Float()
+	case reflect.Bool:
+		return !x.Bool() && y.Bool()
 	}
-	return itemDiffer.diff(changeLog, path.Entry(key), from, to, changeType, options)
+	return fmt.Sprintf("%v", x.Interface()) < fmt.Sprintf("%v", y.Interface())
 }
 
 func newMapDiffer(from, to reflect.Type, config *Config, tag *Tag) (*mapDiffer, error) {
-	isStringIface := from.Key().Kind() == reflect.String && from.Elem().Kind() == reflect.Interface
-	return &mapDiffer{config: config, tag: tag, from: from, to: to, isStringIface: isStringIface}, nil
+	from, to = mapType(from), mapType(to)
+	if from == nil || to == nil {
+		return nil, fmt.Errorf("invalid map types: %v, %v", from, to)
+	}
+	if !from.Key().Comparable() || !to.Key().Comparable() {
+		return nil, fmt.Errorf("unsupported map key types: %s, %s", from.Key().String(), to.Key().String())
+	}
+	result := &mapDiffer{config: config, tag: tag, from: from, to: to}
+	result.isInterface = from.Elem().Kind() == reflect.Interface || to.Elem().Kind() == reflect.Interface
+	result.isSlice = sliceType(from.Elem()) != nil && !config.isLeaf(from.Elem())
+	return result, nil
 }
@@ -0,0 +1,111 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDiffer_Map(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+	type Key struct {
+		Region string
+		Zone   int
+	}
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		expect      *ChangeLog
+	}{
+		{
+			description: "string values",
+			from:        map[string]string{"a": "1", "b": "2", "c": "3"},
+			to:          map[string]string{"a": "1", "b": "20", "d": "4"},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Entry("b"), From: "2", To: "20"},
+				{Type: ChangeTypeDelete, Path: (&Path{}).Entry("c"), From: "3"},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Entry("d"), To: "4"},
+			}},
+		},
+		{
+			description: "int keys with struct pointer values",
+			from:        map[int]*Item{1: {ID: 1, Name: "a"}, 2: {ID: 2, Name: "b"}},
+			to:          map[int]*Item{1: {ID: 1, Name: "x"}, 2: {ID: 2, Name: "b"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).EntryKey(1).Field("Name"), From: "a", To: "x"},
+			}},
+		},
+		{
+			description: "slice values",
+			from:        map[string][]string{"a": {"1", "2"}},
+			to:          map[string][]string{"a": {"1", "3"}, "b": {"4"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Entry("a").Element(1), From: "2", To: "3"},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Entry("b").Element(0), To: "4",
+					container: &Change{Type: ChangeTypeCreate, Path: (&Path{}).Entry("b"), To: &[]string{"4"}}},
+			}},
+		},
+		{
+			description: "struct keys with struct values",
+			from:        map[Key]Item{{"us", 1}: {ID: 1, Name: "a"}},
+			to:          map[Key]Item{{"us", 1}: {ID: 1, Name: "b"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).EntryKey(Key{"us", 1}).Field("Name"), From: "a", To: "b"},
+			}},
+		},
+		{
+			description: "nested maps",
+			from:        map[string]map[string]int{"a": {"x": 1}},
+			to:          map[string]map[string]int{"a": {"x": 2}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Entry("a").Entry("x"), From: 1, To: 2},
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		if !assert.EqualValues(t, testCase.expect, changeLog, testCase.description) {
+			continue
+		}
+		target := reflect.New(reflect.TypeOf(testCase.from))
+		target.Elem().Set(cloneValue(reflect.ValueOf(testCase.from)))
+		if assert.Nil(t, changeLog.Apply(target.Interface()), testCase.description) {
+			assert.EqualValues(t, testCase.to, target.Elem().Interface(), testCase.description)
+		}
+	}
+}
+
+func TestDiffer_Map_Concurrent(t *testing.T) {
+
+	type Item struct {
+		ID    int
+		Attrs map[string]int
+	}
+	differ, err := New(reflect.TypeOf(&Item{}), reflect.TypeOf(&Item{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	from := &Item{ID: 1, Attrs: map[string]int{"a": 1}}
+	to := &Item{ID: 1, Attrs: map[string]int{"a": 2}}
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			assert.EqualValues(t, 1, differ.Diff(from, to).Size())
+		}()
+	}
+	waitGroup.Wait()
+}
@@ -0,0 +1,269 @@
+package godiff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	//Conflict represents a three-way merge conflict
+	Conflict struct {
+		Path   *Path
+		Ours   *Change
+		Theirs *Change
+	}
+
+	merger struct {
+		base    reflect.Value
+		patcher *patcher
+		anchors map[*Change][]interface{}
+	}
+)
+
+//Merge merges base to ours and base to theirs changes into a copy of base, conflicting theirs changes are
+//not applied (ours wins) and are reported as conflicts, indexBy slice elements are matched by key rather than position
+func (d *Differ) Merge(base, ours, theirs interface{}, opts ...Option) (interface{}, []*Conflict, error) {
+	if base == nil {
+		return nil, nil, fmt.Errorf("base was nil")
+	}
+	opts = append(opts, WithMoveDetection(false)) //merge matches changes by base positions
+	oursLog := d.Diff(base, ours, opts...)
+	theirsLog := d.Diff(base, theirs, opts...)
+	aMerger := &merger{base: reflect.ValueOf(base), patcher: newPatcher(d.config), anchors: map[*Change][]interface{}{}}
+	oursChanges := make(map[string]*Change, len(oursLog.Changes))
+	oursKeys := make([]string, 0, len(oursLog.Changes))
+	merged := &ChangeLog{}
+	for _, change := range oursLog.Changes {
+		if change.Error != "" {
+			return nil, nil, fmt.Errorf("failed to diff ours: %v %v", change.Path.String(), change.Error)
+		}
+		key := aMerger.key(change)
+		oursChanges[key] = change
+		oursKeys = append(oursKeys, key)
+		merged.Add(change)
+		aMerger.anchor(ours, change)
+	}
+	var conflicts []*Conflict
+	for _, change := range theirsLog.Changes {
+		if change.Error != "" {
+			return nil, nil, fmt.Errorf("failed to diff theirs: %v %v", change.Path.String(), change.Error)
+		}
+		key := aMerger.key(change)
+		if oursChange, ok := oursChanges[key]; ok {
+			if oursChange.Type != change.Type || !reflect.DeepEqual(oursChange.To, change.To) {
+				conflicts = append(conflicts, &Conflict{Path: change.Path, Ours: oursChange, Theirs: change})
+			}
+			continue
+		}
+		if oursChange := overlapping(oursKeys, oursChanges, key); oursChange != nil {
+			conflictPath := change.Path
+			if oursChange.Path.depth() < conflictPath.depth() {
+				conflictPath = oursChange.Path
+			}
+			conflicts = append(conflicts, &Conflict{Path: conflictPath, Ours: oursChange, Theirs: change})
+			continue
+		}
+		merged.Add(change)
+		aMerger.anchor(theirs, change)
+	}
+	result := cloneValue(aMerger.base)
+	target := result
+	if target.Kind() != reflect.Ptr {
+		target = reflect.New(result.Type())
+		target.Elem().Set(result)
+	}
+	if err := aMerger.apply(target, merged.baseOrder()); err != nil {
+		return nil, conflicts, err
+	}
+	if result.Kind() != reflect.Ptr {
+		return target.Elem().Interface(), conflicts, nil
+	}
+	return result.Interface(), conflicts, nil
+}
+
+//overlapping returns a change on ancestor or descendant path
+func overlapping(keys []string, changes map[string]*Change, key string) *Change {
+	for _, candidate := range keys {
+		if isAncestorKey(candidate, key) || isAncestorKey(key, candidate) {
+			return changes[candidate]
+		}
+	}
+	return nil
+}
+
+func isAncestorKey(ancestor, key string) bool {
+	if len(key) <= len(ancestor) || !strings.HasPrefix(key, ancestor) {
+		return false
+	}
+	next := key[len(ancestor)]
+	return ancestor == "" || next == '.' || next == '['
+}
+
+//apply applies changes to the target, keyed element creates are placed after their preceding neighbour
+func (m *merger) apply(target reflect.Value, changes []*Change) error {
+	for _, change := range changes {
+		if anchors, ok := m.anchors[change]; ok {
+			placed := *change
+			placed.Path = change.Path.at(m.position(target, change, anchors))
+			change = &placed
+		}
+		if err := m.patcher.apply(target, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//anchor records index keys of elements preceding keyed element create in ours or theirs slice (nearest first),
+//since the create 'to' position is relative to that slice rather than to the merged one
+func (m *merger) anchor(side interface{}, change *Change) {
+	if change.Type != ChangeTypeCreate || !change.Path.IsKeyed() {
+		return
+	}
+	slice, err := m.patcher.value(reflect.ValueOf(side), toPositions(change.Path.Path).nodes())
+	if slice = indirect(slice); err != nil || !slice.IsValid() || change.Path.ToIndex > slice.Len() {
+		return
+	}
+	keys := make([]interface{}, 0, change.Path.ToIndex)
+	for i := change.Path.ToIndex - 1; i >= 0; i-- {
+		key, err := indexKey(slice.Index(i), change.Path.IndexBy, m.patcher.config.TagName)
+		if err != nil {
+			return
+		}
+		keys = append(keys, key)
+	}
+	m.anchors[change] = keys
+}
+
+//position returns keyed element create position: after the nearest preceding neighbour present in the target slice,
+//or the first position if none of the neighbours is present
+func (m *merger) position(target reflect.Value, change *Change, anchors []interface{}) int {
+	slice, err := m.patcher.value(target, change.Path.Path.nodes())
+	if slice = indirect(slice); err != nil || !slice.IsValid() {
+		return change.Path.Index
+	}
+	for _, anchor := range anchors {
+		for i := 0; i < slice.Len(); i++ {
+			if key, err := indexKey(slice.Index(i), change.Path.IndexBy, m.patcher.config.TagName); err == nil && key == anchor {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+//toPositions returns path copy with keyed elements at 'to' positions
+func toPositions(path *Path) *Path {
+	if path == nil {
+		return nil
+	}
+	result := *path
+	result.Path = toPositions(path.Path)
+	if path.IsKeyed() && path.ToIndex >= 0 {
+		result.Index = path.ToIndex
+	}
+	return &result
+}
+
+//key returns a change path key, where indexBy slice elements are identified by element key
+func (m *merger) key(change *Change) string {
+	builder := new(strings.Builder)
+	for _, node := range change.Path.nodes() {
+		switch node.Kind {
+		case PathKinField:
+			if builder.Len() > 0 {
+				builder.WriteByte('.')
+			}
+			builder.WriteString(node.Name)
+		case PathKindKey:
+			builder.WriteString(fmt.Sprintf("[%v]", node.Key))
+		case PathKindIndex:
+			if node.IsKeyed() {
+				builder.WriteString(fmt.Sprintf("[%v=%v]", node.IndexBy, node.Key))
+			} else {
+				builder.WriteString(fmt.Sprintf("[%v]", node.Index))
+			}
+		}
+	}
+	return builder.String()
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+//cloneValue returns a deep copy of the value
+func cloneValue(value reflect.Value) reflect.Value {
+	if !value.IsValid() {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.New(value.Type().Elem())
+		result.Elem().Set(cloneValue(value.Elem()))
+		return result
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.New(value.Type()).Elem()
+		result.Set(cloneValue(value.Elem()))
+		return result
+	case reflect.Struct:
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+		if isTimeType(value.Type()) {
+			return result
+		}
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).PkgPath != "" {
+				continue //unexported fields are shallow copied
+			}
+			fieldValue := result.Field(i)
+			fieldValue.Set(cloneValue(fieldValue))
+		}
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(cloneValue(value.Index(i)))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return result
+	}
+	return value
+}
+
+//cloneInterface returns a deep copy of the value stored in a fresh memory,
+//so that the copy does not share memory with the source value (i.e. value read with unsafe pointer)
+func cloneInterface(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	source := reflect.ValueOf(value)
+	result := reflect.New(source.Type()).Elem()
+	result.Set(cloneValue(source))
+	return result.Interface()
+}
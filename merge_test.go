@@ -0,0 +1,100 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_Merge(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+
+	type Document struct {
+		ID    int
+		Title string
+		Body  string
+		Items []*Item `diff:"indexBy=ID"`
+	}
+
+	var testCases = []struct {
+		description string
+		base        *Document
+		ours        *Document
+		theirs      *Document
+		expect      *Document
+		conflicts   []string
+	}{
+		{
+			description: "non overlapping edits",
+			base:        &Document{ID: 1, Title: "title", Body: "body"},
+			ours:        &Document{ID: 1, Title: "new title", Body: "body"},
+			theirs:      &Document{ID: 1, Title: "title", Body: "new body"},
+			expect:      &Document{ID: 1, Title: "new title", Body: "new body"},
+		},
+		{
+			description: "same edits",
+			base:        &Document{ID: 1, Title: "title"},
+			ours:        &Document{ID: 1, Title: "new title"},
+			theirs:      &Document{ID: 1, Title: "new title"},
+			expect:      &Document{ID: 1, Title: "new title"},
+		},
+		{
+			description: "conflicting edits",
+			base:        &Document{ID: 1, Title: "title", Body: "body"},
+			ours:        &Document{ID: 1, Title: "ours title", Body: "body"},
+			theirs:      &Document{ID: 1, Title: "theirs title", Body: "new body"},
+			expect:      &Document{ID: 1, Title: "ours title", Body: "new body"},
+			conflicts:   []string{"Title"},
+		},
+		{
+			description: "indexed elements merged by key",
+			base:        &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}},
+			ours:        &Document{Items: []*Item{{ID: 2, Name: "b"}, {ID: 1, Name: "a1"}}},
+			theirs:      &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b2"}, {ID: 3, Name: "c"}}},
+			expect:      &Document{Items: []*Item{{ID: 1, Name: "a1"}, {ID: 2, Name: "b2"}, {ID: 3, Name: "c"}}},
+		},
+		{
+			description: "indexed elements created on both sides",
+			base:        &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}},
+			ours:        &Document{Items: []*Item{{ID: 0, Name: "x"}, {ID: 1, Name: "a"}, {ID: 2, Name: "b"}}},
+			theirs:      &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "y"}}},
+			expect:      &Document{Items: []*Item{{ID: 0, Name: "x"}, {ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "y"}}},
+		},
+		{
+			description: "indexed elements created after deleted neighbour",
+			base:        &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}},
+			ours:        &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 3, Name: "c"}}},
+			theirs:      &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 4, Name: "d"}, {ID: 5, Name: "e"}, {ID: 3, Name: "c"}}},
+			expect:      &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 4, Name: "d"}, {ID: 5, Name: "e"}, {ID: 3, Name: "c"}}},
+		},
+		{
+			description: "indexed elements conflict",
+			base:        &Document{Items: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}},
+			ours:        &Document{Items: []*Item{{ID: 2, Name: "b"}}},
+			theirs:      &Document{Items: []*Item{{ID: 2, Name: "b"}, {ID: 1, Name: "a2"}}},
+			expect:      &Document{Items: []*Item{{ID: 2, Name: "b"}}},
+			conflicts:   []string{"Items[ID=1]"},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Document{}), reflect.TypeOf(&Document{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		merged, conflicts, err := differ.Merge(testCase.base, testCase.ours, testCase.theirs)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, merged, testCase.description)
+		var actualConflicts []string
+		for _, conflict := range conflicts {
+			actualConflicts = append(actualConflicts, conflict.Path.String())
+		}
+		assert.EqualValues(t, testCase.conflicts, actualConflicts, testCase.description)
+	}
+}
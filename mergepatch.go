@@ -0,0 +1,127 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+//MergePatchContentType defines RFC 7386 JSON merge patch content type
+const MergePatchContentType = "application/merge-patch+json"
+
+//MergePatch returns RFC 7386 JSON merge patch document transforming from into to value,
+//changed slices and created objects are replaced as a whole, deleted fields, entries and emptied values are set to null
+func (d *Differ) MergePatch(from, to interface{}, opts ...Option) ([]byte, error) {
+	changeLog := d.Diff(from, to, opts...)
+	document, err := d.mergePatchDocument(changeLog, to)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+func (d *Differ) mergePatchDocument(changeLog *ChangeLog, to interface{}) (interface{}, error) {
+	aPatcher := newPatcher(d.config)
+	toValue := reflect.ValueOf(to)
+	var document interface{} = map[string]interface{}{}
+	for _, change := range changeLog.Changes {
+		if change.Error != "" {
+			return nil, fmt.Errorf("failed to diff: %v %v", change.Path.String(), change.Error)
+		}
+		if change.container != nil {
+			change = change.container //created or deleted container is patched as a whole
+		}
+		nodes := change.Path.nodes()
+		if len(nodes) == 0 {
+			return change.To, nil
+		}
+		if nodes[0].Kind == PathKindIndex {
+			return interfaceOf(toValue), nil
+		}
+		object, ok := document.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for i, node := range nodes {
+			key := node.mergePatchKey()
+			if i+1 < len(nodes) && nodes[i+1].Kind == PathKindIndex {
+				//RFC 7386 does not address array elements, the whole array is replaced
+				value, err := aPatcher.value(toValue, nodes[:i+1])
+				if err != nil {
+					return nil, err
+				}
+				if node.jsonOmitEmpty && isEmptyJSON(value) {
+					object[key] = nil
+				} else {
+					object[key] = interfaceOf(value)
+				}
+				break
+			}
+			if i == len(nodes)-1 {
+				if change.Type == ChangeTypeDelete || (node.jsonOmitEmpty && isEmptyJSON(reflect.ValueOf(change.To))) {
+					object[key] = nil
+				} else {
+					object[key] = change.To
+				}
+				break
+			}
+			if value, err := aPatcher.value(toValue, nodes[:i+1]); err != nil || !indirect(value).IsValid() || (node.jsonOmitEmpty && isEmptyJSON(value)) {
+				object[key] = nil //parent no longer exists or is omitted as empty
+				break
+			}
+			child, ok := object[key].(map[string]interface{})
+			if !ok {
+				if _, has := object[key]; has {
+					break //already replaced with parent value
+				}
+				child = map[string]interface{}{}
+				object[key] = child
+			}
+			object = child
+		}
+	}
+	return document, nil
+}
+
+//mergePatchKey returns JSON object key for the path node
+func (p *Path) mergePatchKey() string {
+	switch p.Kind {
+	case PathKinField:
+		if p.jsonName != "" {
+			return p.jsonName
+		}
+		return p.Name
+	case PathKindKey:
+		return fmt.Sprintf("%v", p.Key)
+	}
+	return ""
+}
+
+//interfaceOf returns dereferenced value interface or nil
+func interfaceOf(value reflect.Value) interface{} {
+	if value = indirect(value); !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+//isEmptyJSON returns true if value is omitted by encoding/json omitempty option
+func isEmptyJSON(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package godiff
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_MergePatch(t *testing.T) {
+
+	type Item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+
+	type Record struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Dep   Item   `json:"dep"`
+		Ptr   *Item  `json:"ptr,omitempty"`
+		Nums  []int  `json:"nums"`
+		Items []Item `json:"items"`
+		Value interface{}
+		Attrs map[string]int `json:"attrs,omitempty"`
+		Tags  []string       `json:"tags,omitempty"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		expect      string
+	}{
+		{
+			description: "nested fields",
+			from:        &Record{ID: 1, Name: "abc", Dep: Item{ID: 2, Name: "dep"}},
+			to:          &Record{ID: 1, Name: "xyz", Dep: Item{ID: 3, Name: "dep"}},
+			expect:      `{"dep":{"id":3},"name":"xyz"}`,
+		},
+		{
+			description: "slices replaced",
+			from:        &Record{Nums: []int{1, 2, 3}, Items: []Item{{ID: 1}}},
+			to:          &Record{Nums: []int{1, 2}, Items: []Item{{ID: 1, Name: "a"}}},
+			expect:      `{"items":[{"id":1,"name":"a"}],"nums":[1,2]}`,
+		},
+		{
+			description: "removed interface value",
+			from:        &Record{Value: &Item{ID: 1, Name: "a"}},
+			to:          &Record{Value: &Item{ID: 1}},
+			expect:      `{"Value":{"name":null}}`,
+		},
+		{
+			description: "created nested pointer",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1, Ptr: &Item{Name: "a"}},
+			expect:      `{"ptr":{"id":0,"name":"a"}}`,
+		},
+		{
+			description: "deleted nested pointer",
+			from:        &Record{ID: 1, Ptr: &Item{ID: 2, Name: "a"}},
+			to:          &Record{ID: 1},
+			expect:      `{"ptr":null}`,
+		},
+		{
+			description: "updated nested pointer",
+			from:        &Record{ID: 1, Ptr: &Item{ID: 2, Name: "a"}},
+			to:          &Record{ID: 1, Ptr: &Item{ID: 3, Name: "a"}},
+			expect:      `{"ptr":{"id":3}}`,
+		},
+		{
+			description: "emptied omitempty map",
+			from:        &Record{Attrs: map[string]int{"a": 1}},
+			to:          &Record{Attrs: map[string]int{}},
+			expect:      `{"attrs":null}`,
+		},
+		{
+			description: "emptied omitempty slice",
+			from:        &Record{Tags: []string{"a"}},
+			to:          &Record{Tags: []string{}},
+			expect:      `{"tags":null}`,
+		},
+		{
+			description: "updated omitempty map",
+			from:        &Record{Attrs: map[string]int{"a": 1, "b": 2}},
+			to:          &Record{Attrs: map[string]int{"b": 2}},
+			expect:      `{"attrs":{"a":null}}`,
+		},
+		{
+			description: "map entries",
+			from:        map[string]interface{}{"k1": "v1", "k2": "v2"},
+			to:          map[string]interface{}{"k1": "v1", "k3": "v3"},
+			expect:      `{"k2":null,"k3":"v3"}`,
+		},
+		{
+			description: "no changes",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1},
+			expect:      `{}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := differ.MergePatch(testCase.from, testCase.to)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, string(actual), testCase.description)
+
+		var document, patch, expect interface{}
+		fromJSON, _ := json.Marshal(testCase.from)
+		toJSON, _ := json.Marshal(testCase.to)
+		assert.Nil(t, json.Unmarshal(fromJSON, &document), testCase.description)
+		assert.Nil(t, json.Unmarshal(actual, &patch), testCase.description)
+		assert.Nil(t, json.Unmarshal(toJSON, &expect), testCase.description)
+		assert.EqualValues(t, expect, applyMergePatch(document, patch), testCase.description)
+	}
+}
+
+//applyMergePatch applies RFC 7386 merge patch to decoded JSON document
+func applyMergePatch(document, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = applyMergePatch(object[key], value)
+	}
+	return object
+}
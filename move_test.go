@@ -0,0 +1,96 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_Move(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+	type Board struct {
+		ID    int
+		Items []Item   `diff:"indexBy=ID"`
+		Steps []string `diff:"align=lcs"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Board
+		to          *Board
+		expect      *ChangeLog
+		jsonPatch   string
+	}{
+		{
+			description: "indexBy move with update",
+			from:        &Board{Items: []Item{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}}},
+			to:          &Board{Items: []Item{{1, "a"}, {4, "x"}, {2, "b"}, {3, "c"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Items").KeyedElement("ID", 4, 3, 1).Field("Name"), From: "d", To: "x"},
+				{Type: ChangeTypeMove, Path: (&Path{}).Field("Items").KeyedElement("ID", 4, 3, 1), From: 3, To: 1},
+			}},
+			jsonPatch: `[{"op":"replace","path":"/Items/3/Name","value":"x"},{"op":"move","path":"/Items/1","from":"/Items/3"}]`,
+		},
+		{
+			description: "indexBy move with delete and create",
+			from:        &Board{Items: []Item{{1, "a"}, {2, "b"}, {3, "c"}}},
+			to:          &Board{Items: []Item{{3, "c"}, {5, "e"}, {1, "a"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Items").KeyedElement("ID", 2, 1, -1), From: Item{2, "b"}},
+				{Type: ChangeTypeMove, Path: (&Path{}).Field("Items").KeyedElement("ID", 1, 0, 2), From: 0, To: 2},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Items").KeyedElement("ID", 5, -1, 1), To: Item{5, "e"}},
+			}},
+		},
+		{
+			description: "lcs move",
+			from:        &Board{Steps: []string{"build", "test", "lint", "deploy"}},
+			to:          &Board{Steps: []string{"lint", "build", "test", "deploy", "notify"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeMove, Path: (&Path{}).Field("Steps").Element(2), From: 2, To: 0},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Steps").Element(4), To: "notify"},
+			}},
+		},
+		{
+			description: "lcs move with create before",
+			from:        &Board{Steps: []string{"a", "b", "c", "d"}},
+			to:          &Board{Steps: []string{"x", "d", "a", "b", "c"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeMove, Path: (&Path{}).Field("Steps").Element(3), From: 3, To: 1},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Steps").Element(0), To: "x"},
+			}},
+			jsonPatch: `[{"op":"move","path":"/Steps/0","from":"/Steps/3"},{"op":"add","path":"/Steps/0","value":"x"}]`,
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Board{}), reflect.TypeOf(&Board{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to, WithMoveDetection(true))
+		if !assert.EqualValues(t, testCase.expect, changeLog, testCase.description) {
+			continue
+		}
+		if testCase.jsonPatch != "" {
+			data, err := changeLog.JSONPatch()
+			assert.Nil(t, err, testCase.description)
+			assert.EqualValues(t, testCase.jsonPatch, string(data), testCase.description)
+		}
+		target := cloneValue(reflect.ValueOf(testCase.from)).Interface()
+		if assert.Nil(t, changeLog.Apply(target), testCase.description) {
+			assert.EqualValues(t, testCase.to, target, testCase.description)
+		}
+		inverted := cloneValue(reflect.ValueOf(testCase.to)).Interface()
+		if assert.Nil(t, changeLog.Invert().Apply(inverted), testCase.description) {
+			assert.EqualValues(t, testCase.from, inverted, testCase.description)
+		}
+	}
+
+	changeLog := differ.Diff(&Board{Steps: []string{"a", "b"}}, &Board{Steps: []string{"b", "a"}})
+	assert.EqualValues(t, 2, changeLog.Size())
+	assert.NotEqual(t, ChangeTypeMove, changeLog.Changes[0].Type)
+}
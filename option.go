@@ -1,11 +1,14 @@
 package godiff
 
+import "reflect"
+
 //ConfigOption represents an option
 type ConfigOption func(config *Config)
 
 type Options struct {
 	setMarker    bool
 	shallow      bool
+	detectMoves  bool
 	nullifyEmpty *bool
 	depth        int
 }
@@ -41,6 +44,13 @@ func WithSetMarker(f bool) Option {
 	}
 }
 
+//WithMoveDetection updated option to report reordered indexBy (or align=lcs) slice elements as moves, disabled by default
+func WithMoveDetection(f bool) Option {
+	return func(options *Options) {
+		options.detectMoves = f
+	}
+}
+
 func WithShallow(f bool) Option {
 	return func(options *Options) {
 		options.shallow = f
@@ -61,6 +71,58 @@ func WithConfig(cfg *Config) ConfigOption {
 	}
 }
 
+//WithStrictMode updated config strict mode, strict mode reports unequal string and non-string field values as an error
+func WithStrictMode(flag bool) ConfigOption {
+	return func(config *Config) {
+		config.StrictMode = flag
+	}
+}
+
+//WithTimeLayout updated config time layout, time values are compared and rendered in change records with the layout
+func WithTimeLayout(layout string) ConfigOption {
+	return func(config *Config) {
+		config.TimeLayout = layout
+	}
+}
+
+//WithPrecision updated config float comparison precision
+func WithPrecision(precision int) ConfigOption {
+	return func(config *Config) {
+		config.Precision = &precision
+	}
+}
+
+//WithComparator registers comparator for the type, values of that type are compared as a whole with the comparator
+func WithComparator(t reflect.Type, comparator Comparator) ConfigOption {
+	return func(config *Config) {
+		comparators := make(map[reflect.Type]Comparator, len(config.comparators)+1)
+		for k, v := range config.comparators {
+			comparators[k] = v
+		}
+		comparators[t] = comparator
+		config.comparators = comparators
+	}
+}
+
+//WithNamedComparator registers comparator for the name, referenced by field tag, i.e. diff:"comparator=name"
+func WithNamedComparator(name string, comparator Comparator) ConfigOption {
+	return func(config *Config) {
+		comparators := make(map[string]Comparator, len(config.namedComparators)+1)
+		for k, v := range config.namedComparators {
+			comparators[k] = v
+		}
+		comparators[name] = comparator
+		config.namedComparators = comparators
+	}
+}
+
+//WithDefaultComparator updated config with comparator used for values without type comparator
+func WithDefaultComparator(comparator Comparator) ConfigOption {
+	return func(config *Config) {
+		config.defaultComparator = comparator
+	}
+}
+
 //WithRegistry updated config with registry
 func WithRegistry(registry *Registry) ConfigOption {
 	return func(config *Config) {
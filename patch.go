@@ -0,0 +1,480 @@
+package godiff
+
+import (
+	"fmt"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+type patcher struct {
+	config *Config
+	fields map[reflect.Type]map[string]*xunsafe.Field
+}
+
+//Apply applies change log to the target, target has to be a non nil pointer
+func (l *ChangeLog) Apply(target interface{}, opts ...ConfigOption) error {
+	return l.apply(target, newConfig(opts))
+}
+
+//Patch applies change log to the target with the differ config
+func (d *Differ) Patch(target interface{}, changeLog *ChangeLog) error {
+	return changeLog.apply(target, d.config)
+}
+
+func (l *ChangeLog) apply(target interface{}, config *Config) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("invalid patch target: expected non nil pointer, but had: %T", target)
+	}
+	aPatcher := newPatcher(config)
+	containers := map[*Change]bool{}
+	for _, change := range l.sequenced() {
+		if change.Error != "" || change.Path == nil {
+			continue
+		}
+		if container := change.container; container != nil && container.Path.Kind != PathKindRoot {
+			if containers[container] {
+				continue
+			}
+			containers[container] = true
+			change = container //created or deleted container is applied as a whole
+		}
+		if err := aPatcher.apply(value, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//baseOrder returns changes with slice element positions relative to the same base value in sequence order:
+//element deletes ('from' positions) and creates ('to' positions) go last
+func (l *ChangeLog) baseOrder() []*Change {
+	var result, creates, deletes []*Change
+	for _, change := range l.Changes {
+		if change.Error != "" || change.Path == nil {
+			continue
+		}
+		switch change.Type {
+		case ChangeTypeCreate:
+			if change.Path.Kind == PathKindIndex {
+				creates = append(creates, change)
+				continue
+			}
+		case ChangeTypeDelete:
+			if change.Path.element() != nil {
+				deletes = append(deletes, change)
+				continue
+			}
+		}
+		result = append(result, change)
+	}
+	//element deletes use 'from' positions: deeper and higher indexes go first not to shift remaining elements
+	sort.SliceStable(deletes, func(i, j int) bool {
+		return deletes[i].Path.element().compareElement(deletes[j].Path.element()) > 0
+	})
+	//element creates use 'to' positions: shallower and lower indexes go first
+	sort.SliceStable(creates, func(i, j int) bool {
+		return creates[i].Path.compareElement(creates[j].Path) < 0
+	})
+	result = append(result, deletes...)
+	return append(result, creates...)
+}
+
+func (p *patcher) apply(target reflect.Value, change *Change) error {
+	_, err := p.patch(target, change.Path.nodes(), change)
+	if err != nil {
+		return fmt.Errorf("failed to apply %v %v: %w", change.Type, change.Path.String(), err)
+	}
+	return nil
+}
+
+//patch applies change to the value and returns patched value, nodes are relative to the value
+func (p *patcher) patch(value reflect.Value, nodes []*Path, change *Change) (reflect.Value, error) {
+	if len(nodes) == 0 {
+		if change.Type == ChangeTypeDelete {
+			return reflect.Zero(value.Type()), nil
+		}
+		return assignableValue(change.To, value.Type())
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			if change.Type == ChangeTypeDelete {
+				return value, nil
+			}
+			value = reflect.New(value.Type().Elem())
+		}
+		elem, err := p.patch(value.Elem(), nodes, change)
+		if err != nil {
+			return value, err
+		}
+		if isEmptied(elem, change) {
+			return reflect.Zero(value.Type()), nil
+		}
+		value.Elem().Set(elem)
+		return value, nil
+	case reflect.Interface:
+		if value.IsNil() {
+			if change.Type == ChangeTypeDelete {
+				return value, nil
+			}
+			container, err := newContainer(nodes[0])
+			if err != nil {
+				return value, err
+			}
+			value = container
+		} else {
+			value = value.Elem()
+		}
+		return p.patch(value, nodes, change)
+	case reflect.Struct:
+		return p.patchStruct(value, nodes, change)
+	case reflect.Map:
+		return p.patchMap(value, nodes, change)
+	case reflect.Slice:
+		return p.patchSlice(value, nodes, change)
+	case reflect.Array:
+		return p.patchArray(value, nodes, change)
+	}
+	return value, fmt.Errorf("unsupported path node %v for type: %s", nodes[0].String(), value.Type().String())
+}
+
+func (p *patcher) patchStruct(value reflect.Value, nodes []*Path, change *Change) (reflect.Value, error) {
+	node := nodes[0]
+	if node.Kind != PathKinField {
+		return value, fmt.Errorf("invalid path node %v for struct type: %s", node.String(), value.Type().String())
+	}
+	xField := p.field(value.Type(), node.Name)
+	if xField == nil {
+		return value, fmt.Errorf("failed to lookup field %v in %s", node.Name, value.Type().String())
+	}
+	value = addressable(value)
+	fieldValue := reflect.NewAt(xField.Type, xField.Pointer(unsafe.Pointer(value.UnsafeAddr()))).Elem()
+	patched, err := p.patch(fieldValue, nodes[1:], change)
+	if err != nil {
+		return value, err
+	}
+	fieldValue.Set(patched)
+	return value, nil
+}
+
+func (p *patcher) patchMap(value reflect.Value, nodes []*Path, change *Change) (reflect.Value, error) {
+	node := nodes[0]
+	mapType := value.Type()
+	key, err := node.mapKey(mapType.Key())
+	if err != nil {
+		return value, err
+	}
+	if value.IsNil() {
+		if change.Type == ChangeTypeDelete {
+			return value, nil
+		}
+		value = reflect.MakeMap(mapType)
+	}
+	if len(nodes) == 1 && change.Type == ChangeTypeDelete {
+		value.SetMapIndex(key, reflect.Value{})
+		return value, nil
+	}
+	item := reflect.New(mapType.Elem()).Elem()
+	if existing := value.MapIndex(key); existing.IsValid() {
+		item.Set(existing)
+	} else if change.Type == ChangeTypeDelete {
+		return value, nil
+	}
+	patched, err := p.patch(item, nodes[1:], change)
+	if err != nil {
+		return value, err
+	}
+	if isEmptied(patched, change) {
+		value.SetMapIndex(key, reflect.Value{})
+		return value, nil
+	}
+	value.SetMapIndex(key, patched)
+	return value, nil
+}
+
+func (p *patcher) patchSlice(value reflect.Value, nodes []*Path, change *Change) (reflect.Value, error) {
+	node := nodes[0]
+	index, err := p.elementIndex(value, node, change.Type == ChangeTypeCreate && len(nodes) == 1)
+	if err != nil {
+		return value, err
+	}
+	if index < 0 {
+		return value, fmt.Errorf("invalid index: %v", index)
+	}
+	if len(nodes) == 1 {
+		switch change.Type {
+		case ChangeTypeDelete:
+			if index >= value.Len() {
+				return value, fmt.Errorf("index %v out of range: %v", index, value.Len())
+			}
+			return reflect.AppendSlice(value.Slice(0, index), value.Slice(index+1, value.Len())), nil
+		case ChangeTypeMove:
+			if index >= value.Len() {
+				return value, fmt.Errorf("index %v out of range: %v", index, value.Len())
+			}
+			to, err := assignableValue(change.To, reflect.TypeOf(0))
+			if err != nil {
+				return value, err
+			}
+			return moveElement(value, index, int(to.Int()))
+		case ChangeTypeCreate:
+			item, err := assignableValue(change.To, value.Type().Elem())
+			if err != nil {
+				return value, err
+			}
+			if index >= value.Len() {
+				return reflect.Append(value, item), nil
+			}
+			result := reflect.MakeSlice(value.Type(), 0, value.Len()+1)
+			result = reflect.AppendSlice(result, value.Slice(0, index))
+			result = reflect.Append(result, item)
+			return reflect.AppendSlice(result, value.Slice(index, value.Len())), nil
+		}
+	}
+	if index >= value.Len() {
+		if change.Type == ChangeTypeDelete {
+			return value, nil
+		}
+		value = growSlice(value, index+1)
+	}
+	item := value.Index(index)
+	patched, err := p.patch(item, nodes[1:], change)
+	if err != nil {
+		return value, err
+	}
+	if isEmptied(patched, change) {
+		return reflect.AppendSlice(value.Slice(0, index), value.Slice(index+1, value.Len())), nil
+	}
+	item.Set(patched)
+	return value, nil
+}
+
+//patchArray patches array element, element removal, insertion and move are applied to array copy as a slice,
+//where removed elements are zero filled at the end and elements beyond array length are dropped
+func (p *patcher) patchArray(value reflect.Value, nodes []*Path, change *Change) (reflect.Value, error) {
+	if len(nodes) > 1 || change.Type == ChangeTypeUpdate {
+		index, err := p.elementIndex(value, nodes[0], false)
+		if err != nil {
+			return value, err
+		}
+		if index < 0 || index >= value.Len() {
+			return value, fmt.Errorf("index %v out of range: %v", index, value.Len())
+		}
+		value = addressable(value)
+		item := value.Index(index)
+		patched, err := p.patch(item, nodes[1:], change)
+		if err != nil {
+			return value, err
+		}
+		item.Set(patched)
+		return value, nil
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), value.Len(), value.Len())
+	reflect.Copy(slice, value)
+	patched, err := p.patchSlice(slice, nodes, change)
+	if err != nil {
+		return value, err
+	}
+	result := reflect.New(value.Type()).Elem()
+	reflect.Copy(result, patched)
+	return result, nil
+}
+
+//value returns value at path nodes relative to the supplied value
+func (p *patcher) value(value reflect.Value, nodes []*Path) (reflect.Value, error) {
+	for _, node := range nodes {
+		if value = indirect(value); !value.IsValid() {
+			return value, fmt.Errorf("%v parent was nil", node.String())
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			if node.Kind != PathKinField {
+				return reflect.Value{}, fmt.Errorf("invalid path node %v for struct type: %s", node.String(), value.Type().String())
+			}
+			xField := p.field(value.Type(), node.Name)
+			if xField == nil {
+				return reflect.Value{}, fmt.Errorf("failed to lookup field %v in %s", node.Name, value.Type().String())
+			}
+			value = addressable(value)
+			value = reflect.NewAt(xField.Type, xField.Pointer(unsafe.Pointer(value.UnsafeAddr()))).Elem()
+		case reflect.Map:
+			key, err := node.mapKey(value.Type().Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if value = value.MapIndex(key); !value.IsValid() {
+				return value, fmt.Errorf("%v does not exist", node.String())
+			}
+		case reflect.Slice, reflect.Array:
+			index, err := p.elementIndex(value, node, false)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if index < 0 || index >= value.Len() {
+				return reflect.Value{}, fmt.Errorf("index %v out of range: %v", index, value.Len())
+			}
+			value = value.Index(index)
+		default:
+			return reflect.Value{}, fmt.Errorf("unsupported path node %v for type: %s", node.String(), value.Type().String())
+		}
+	}
+	return value, nil
+}
+
+//elementIndex returns slice element position for path node, keyed element node or key node parsed from keyed element path,
+//i.e. [ID=42], is matched with elements index key, unmatched key of element being created resolves to the node position
+//(or slice length for parsed node)
+func (p *patcher) elementIndex(value reflect.Value, node *Path, creating bool) (int, error) {
+	var by string
+	var key interface{}
+	switch {
+	case node.IsKeyed():
+		by, key = node.IndexBy, node.Key
+	case node.Kind == PathKindIndex:
+		return node.Index, nil
+	default:
+		var ok bool
+		if by, key, ok = node.keyedIndex(); !ok {
+			return 0, fmt.Errorf("invalid path node %v for slice type: %s", node.String(), value.Type().String())
+		}
+	}
+	for i := 0; i < value.Len(); i++ {
+		itemKey, err := indexKey(value.Index(i), by, p.config.TagName)
+		if err != nil {
+			return 0, err
+		}
+		if sameKey(itemKey, key) {
+			return i, nil
+		}
+	}
+	if creating {
+		if node.IsKeyed() && node.Index <= value.Len() {
+			return node.Index, nil
+		}
+		return value.Len(), nil
+	}
+	return 0, fmt.Errorf("%v does not exist", node.String())
+}
+
+//sameKey returns true if element index key matches path key, keys of different types (i.e. parsed from path string) are compared as text
+func sameKey(itemKey, key interface{}) bool {
+	if itemKey != nil && key != nil && reflect.TypeOf(itemKey) == reflect.TypeOf(key) {
+		return itemKey == key
+	}
+	return fmt.Sprintf("%v", itemKey) == fmt.Sprintf("%v", key)
+}
+
+//field returns struct field matching path name, renamed (diff tag name) fields take precedence
+func (p *patcher) field(structType reflect.Type, name string) *xunsafe.Field {
+	fields, ok := p.fields[structType]
+	if !ok {
+		xStruct := xunsafe.NewStruct(structType)
+		fields = make(map[string]*xunsafe.Field, len(xStruct.Fields))
+		for i := range xStruct.Fields {
+			xField := &xStruct.Fields[i]
+			if _, ok := fields[xField.Name]; !ok {
+				fields[xField.Name] = xField
+			}
+			if tag, err := ParseTag(xField.Tag.Get(p.config.TagName)); err == nil && tag.Name != "" {
+				fields[tag.Name] = xField
+			}
+		}
+		p.fields[structType] = fields
+	}
+	return fields[name]
+}
+
+func newPatcher(config *Config) *patcher {
+	return &patcher{config: config, fields: map[reflect.Type]map[string]*xunsafe.Field{}}
+}
+
+func newContainer(node *Path) (reflect.Value, error) {
+	switch node.Kind {
+	case PathKindKey:
+		return reflect.MakeMap(stringMapType), nil
+	case PathKindIndex:
+		return reflect.MakeSlice(reflect.TypeOf([]interface{}{}), 0, 0), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unable to create container for path node: %v", node.String())
+}
+
+//isEmptied returns true if nested delete left zero value, differ reports removed struct as its non zero fields deletes
+func isEmptied(value reflect.Value, change *Change) bool {
+	return change.Type == ChangeTypeDelete && value.IsZero()
+}
+
+func addressable(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value
+	}
+	result := reflect.New(value.Type()).Elem()
+	result.Set(value)
+	return result
+}
+
+//moveElement removes slice element at from position and inserts it at to position
+func moveElement(value reflect.Value, from, to int) (reflect.Value, error) {
+	if to < 0 || to >= value.Len() {
+		return value, fmt.Errorf("index %v out of range: %v", to, value.Len())
+	}
+	rest := reflect.MakeSlice(value.Type(), 0, value.Len())
+	rest = reflect.AppendSlice(rest, value.Slice(0, from))
+	rest = reflect.AppendSlice(rest, value.Slice(from+1, value.Len()))
+	result := reflect.MakeSlice(value.Type(), 0, value.Len())
+	result = reflect.AppendSlice(result, rest.Slice(0, to))
+	result = reflect.Append(result, value.Index(from))
+	return reflect.AppendSlice(result, rest.Slice(to, rest.Len())), nil
+}
+
+func growSlice(value reflect.Value, size int) reflect.Value {
+	if value.Len() >= size {
+		return value
+	}
+	return reflect.AppendSlice(value, reflect.MakeSlice(value.Type(), size-value.Len(), size-value.Len()))
+}
+
+//assignableValue converts value to the target type
+func assignableValue(value interface{}, target reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(target), nil
+	}
+	if actual, ok := value.(reflect.Value); ok {
+		value = actual.Interface()
+	}
+	aValue := cloneValue(reflect.ValueOf(value)) //patched value does not share memory with the change
+	if aValue.Type().AssignableTo(target) {
+		return aValue, nil
+	}
+	if aValue.Kind() == reflect.Ptr {
+		if aValue.IsNil() {
+			return reflect.Zero(target), nil
+		}
+		return assignableValue(aValue.Elem().Interface(), target)
+	}
+	if target.Kind() == reflect.Ptr {
+		elem, err := assignableValue(value, target.Elem())
+		if err != nil {
+			return elem, err
+		}
+		result := reflect.New(target.Elem())
+		result.Elem().Set(elem)
+		return result, nil
+	}
+	if isConvertible(aValue.Type(), target) {
+		return aValue.Convert(target), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unable to assign %T to %s", value, target.String())
+}
+
+func isConvertible(from, to reflect.Type) bool {
+	if !from.ConvertibleTo(to) {
+		return false
+	}
+	if to.Kind() == reflect.String {
+		return from.Kind() == reflect.String || from.Kind() == reflect.Slice
+	}
+	return true
+}
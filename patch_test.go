@@ -0,0 +1,102 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestChangeLog_Apply(t *testing.T) {
+
+	type Item struct {
+		ID   int
+		Name string
+	}
+
+	type Group struct {
+		Nums []int
+	}
+
+	type Record struct {
+		ID    int
+		Name  string `diff:"name=title"`
+		Dep   Item
+		Ptr   *Item
+		Group *Group
+		Nums  []int
+		Items []*Item
+		Value interface{}
+	}
+
+	fromAttrs := map[string]interface{}{"k1": "v1", "k2": "v2"}
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		target      interface{}
+	}{
+		{
+			description: "basic fields",
+			from:        &Record{ID: 1, Name: "abc"},
+			to:          &Record{ID: 2, Name: "xyz"},
+		},
+		{
+			description: "nested struct",
+			from:        &Record{ID: 1, Dep: Item{ID: 3}},
+			to:          &Record{ID: 1, Dep: Item{ID: 10, Name: "dep"}},
+		},
+		{
+			description: "created nested pointer",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1, Ptr: &Item{ID: 10, Name: "dep"}},
+		},
+		{
+			description: "updated nested pointer",
+			from:        &Record{ID: 1, Ptr: &Item{ID: 3, Name: "dep"}},
+			to:          &Record{ID: 1, Ptr: &Item{ID: 10, Name: "dep"}},
+		},
+		{
+			description: "deleted nested pointer",
+			from:        &Record{ID: 1, Ptr: &Item{ID: 3, Name: "dep"}, Group: &Group{Nums: []int{1}}},
+			to:          &Record{ID: 1},
+		},
+		{
+			description: "created nested pointer with zero fields",
+			from:        &Record{ID: 1},
+			to:          &Record{ID: 1, Ptr: &Item{Name: "dep"}, Group: &Group{Nums: []int{0}}},
+		},
+		{
+			description: "slice elements",
+			from:        &Record{Nums: []int{1, 2, 3, 4}, Items: []*Item{{ID: 1, Name: "a"}}},
+			to:          &Record{Nums: []int{1, 5}, Items: []*Item{{ID: 1, Name: "b"}, {ID: 2, Name: "c"}}},
+		},
+		{
+			description: "interface value",
+			from:        &Record{Value: &Item{ID: 1, Name: "a"}},
+			to:          &Record{Value: &Item{ID: 1, Name: "b"}},
+		},
+		{
+			description: "map entries",
+			from:        fromAttrs,
+			to:          map[string]interface{}{"k1": "v1.1", "k3": "v3"},
+			target:      &fromAttrs,
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		target := testCase.target
+		if target == nil {
+			target = testCase.from
+		}
+		err = differ.Patch(target, changeLog)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, reflect.Indirect(reflect.ValueOf(testCase.to)).Interface(), reflect.ValueOf(target).Elem().Interface(), testCase.description)
+	}
+}
@@ -2,6 +2,7 @@ package godiff
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 )
@@ -27,6 +28,14 @@ type (
 		Name  string      `json:",omitempty"`
 		Index int         `json:",omitempty"`
 		Key   interface{} `json:",omitempty"`
+		//IndexBy, FromIndex and ToIndex are set for indexed slice element, where Key holds element index key,
+		//FromIndex and ToIndex are element positions in from and to slices, -1 if element does not exist
+		IndexBy   string `json:",omitempty"`
+		FromIndex int    `json:",omitempty"`
+		ToIndex   int    `json:",omitempty"`
+
+		jsonName      string
+		jsonOmitEmpty bool
 	}
 )
 
@@ -40,11 +49,49 @@ func (p *Path) Entry(name string) *Path {
 	return &Path{Key: name, Kind: PathKindKey, Path: p}
 }
 
+//EntryKey adds map entry node with non string key
+func (p *Path) EntryKey(key interface{}) *Path {
+	return &Path{Key: key, Kind: PathKindKey, Path: p}
+}
+
 //Element adds slice element node
 func (p *Path) Element(index int) *Path {
 	return &Path{Index: index, Kind: PathKindIndex, Path: p}
 }
 
+//KeyedElement adds indexed slice element node, the node index is from position or to position for created element
+func (p *Path) KeyedElement(by string, key interface{}, fromIndex, toIndex int) *Path {
+	index := fromIndex
+	if index < 0 {
+		index = toIndex
+	}
+	return &Path{Index: index, Kind: PathKindIndex, Key: key, IndexBy: by, FromIndex: fromIndex, ToIndex: toIndex, Path: p}
+}
+
+//IsKeyed returns true if path node identifies indexed slice element by key
+func (p *Path) IsKeyed() bool {
+	return p.Kind == PathKindIndex && p.IndexBy != ""
+}
+
+//Get returns value at the path, struct fields are matched by diff tag name or field name
+func (p *Path) Get(value interface{}, opts ...ConfigOption) (interface{}, error) {
+	result, err := newPatcher(newConfig(opts)).value(reflect.ValueOf(value), p.nodes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %v: %w", p.String(), err)
+	}
+	if !result.IsValid() {
+		return nil, nil
+	}
+	return result.Interface(), nil
+}
+
+//Set sets value at the path, ptr has to be a non nil pointer, nil pointers, maps and slices on the path are allocated
+func (p *Path) Set(ptr interface{}, value interface{}, opts ...ConfigOption) error {
+	changeLog := &ChangeLog{}
+	changeLog.AddUpdate(p, nil, value)
+	return changeLog.apply(ptr, newConfig(opts))
+}
+
 //String stringifies a path
 func (p *Path) String() string {
 	builder := new(strings.Builder)
@@ -61,23 +108,275 @@ func (p *Path) stringify(builder *strings.Builder) {
 		builder.WriteByte('[')
 		switch actual := p.Key.(type) {
 		case string:
-			builder.WriteString(actual)
+			builder.WriteString(pathEscaper.Replace(actual))
 		case int:
 			builder.WriteString(strconv.Itoa(actual))
 		case int64:
 			builder.WriteString(strconv.Itoa(int(actual)))
 		default:
-			builder.WriteString(fmt.Sprintf("%v", actual))
+			builder.WriteString(pathEscaper.Replace(fmt.Sprintf("%v", actual)))
 		}
 		builder.WriteByte(']')
 	case PathKinField:
 		if builder.Len() > 0 {
 			builder.WriteByte('.')
 		}
-		builder.WriteString(p.Name)
+		builder.WriteString(pathEscaper.Replace(p.Name))
 	case PathKindIndex:
 		builder.WriteByte('[')
-		builder.WriteString(strconv.Itoa(p.Index))
+		if p.IsKeyed() {
+			builder.WriteString(pathEscaper.Replace(p.IndexBy))
+			builder.WriteByte('=')
+			builder.WriteString(pathEscaper.Replace(fmt.Sprintf("%v", p.Key)))
+		} else if token, ok := p.Key.(string); ok {
+			builder.WriteString(token)
+		} else {
+			builder.WriteString(strconv.Itoa(p.Index))
+		}
 		builder.WriteByte(']')
 	}
 }
+
+//pathEscaper escapes path separators in field names and keys
+var pathEscaper = strings.NewReplacer(`\`, `\\`, `.`, `\.`, `[`, `\[`, `]`, `\]`)
+
+//ParsePath parses path string produced by Path.String, bracket content with digits only is parsed as an index,
+//otherwise as a string key; index token with leading zeros is also kept as the node key for string keyed maps
+func ParsePath(path string) (*Path, error) {
+	result := &Path{}
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '[':
+			token, next, err := scanPathToken(path, i+1, "]")
+			if err != nil {
+				return nil, err
+			}
+			if next == len(path) {
+				return nil, fmt.Errorf("invalid path %q: unterminated '['", path)
+			}
+			if index, err := strconv.Atoi(token); err == nil && isDigits(token) {
+				result = result.Element(index)
+				if token != strconv.Itoa(index) {
+					result.Key = token
+				}
+			} else {
+				result = &Path{Kind: PathKindKey, Key: token, Path: result}
+			}
+			i = next + 1
+		case '.', ']':
+			if path[i] == ']' || result.Kind == PathKindRoot {
+				return nil, fmt.Errorf("invalid path %q: unexpected %q at %v", path, path[i], i)
+			}
+			i++
+			fallthrough
+		default:
+			if i > 0 && path[i-1] != '.' {
+				return nil, fmt.Errorf("invalid path %q: unexpected %q at %v", path, path[i], i)
+			}
+			token, next, err := scanPathToken(path, i, ".[]")
+			if err != nil {
+				return nil, err
+			}
+			if token == "" {
+				return nil, fmt.Errorf("invalid path %q: empty field name at %v", path, i)
+			}
+			result = result.Field(token)
+			i = next
+		}
+	}
+	return result, nil
+}
+
+//scanPathToken returns unescaped token and terminator position
+func scanPathToken(path string, offset int, terminators string) (string, int, error) {
+	token := new(strings.Builder)
+	for i := offset; i < len(path); i++ {
+		c := path[i]
+		if c == '\\' {
+			if i+1 == len(path) {
+				return "", i, fmt.Errorf("invalid path %q: unterminated escape", path)
+			}
+			i++
+			token.WriteByte(path[i])
+			continue
+		}
+		if strings.IndexByte(terminators, c) != -1 {
+			return token.String(), i, nil
+		}
+		token.WriteByte(c)
+	}
+	return token.String(), len(path), nil
+}
+
+//mapKey returns map key for key node, index node (parsed from path string with numeric key) is also accepted
+func (p *Path) mapKey(keyType reflect.Type) (reflect.Value, error) {
+	var key interface{}
+	switch p.Kind {
+	case PathKindKey:
+		key = p.Key
+	case PathKindIndex:
+		key = p.Index
+		if token, ok := p.Key.(string); ok && !p.IsKeyed() {
+			key = token
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("invalid path node %v for map key: %s", p.String(), keyType.String())
+	}
+	if text, ok := key.(string); ok && keyType.Kind() != reflect.String {
+		return parseKey(text, keyType)
+	}
+	if index, ok := key.(int); ok && keyType.Kind() == reflect.String {
+		return reflect.ValueOf(strconv.Itoa(index)).Convert(keyType), nil
+	}
+	return assignableValue(key, keyType)
+}
+
+//parseKey parses text into map key
+func parseKey(token string, keyType reflect.Type) (reflect.Value, error) {
+	key := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		key.SetString(token)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return key, fmt.Errorf("invalid key %v: %w", token, err)
+		}
+		key.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(token, 10, 64)
+		if err != nil {
+			return key, fmt.Errorf("invalid key %v: %w", token, err)
+		}
+		key.SetUint(value)
+	default:
+		return key, fmt.Errorf("unsupported key type: %s", keyType.String())
+	}
+	return key, nil
+}
+
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+//nodes returns path nodes from the root (excluded) to the leaf
+func (p *Path) nodes() []*Path {
+	var result []*Path
+	for node := p; node != nil && node.Kind != PathKindRoot; node = node.Path {
+		result = append(result, node)
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+//element returns the closest slice element node
+func (p *Path) element() *Path {
+	for node := p; node != nil; node = node.Path {
+		if node.Kind == PathKindIndex {
+			return node
+		}
+	}
+	return nil
+}
+
+//at returns copy of the element node with the supplied position
+func (p *Path) at(index int) *Path {
+	result := *p
+	result.Index = index
+	return &result
+}
+
+//inverted returns path copy with keyed elements from and to positions swapped
+func (p *Path) inverted() *Path {
+	if p == nil {
+		return nil
+	}
+	result := *p
+	result.Path = p.Path.inverted()
+	if p.IsKeyed() {
+		result = *result.Path.KeyedElement(p.IndexBy, p.Key, p.ToIndex, p.FromIndex)
+	}
+	return &result
+}
+
+//keyedIndex splits key node parsed from keyed element path string, i.e. [ID=42], into index by and key
+func (p *Path) keyedIndex() (string, string, bool) {
+	text, ok := p.Key.(string)
+	if p.Kind != PathKindKey || !ok {
+		return "", "", false
+	}
+	position := strings.IndexByte(text, '=')
+	if position <= 0 {
+		return "", "", false
+	}
+	return text[:position], text[position+1:], true
+}
+
+//compare compares paths node by node, nil path goes first
+func (p *Path) compare(other *Path) int {
+	if p == nil || other == nil {
+		return compareBool(p != nil, other != nil)
+	}
+	nodes, otherNodes := p.nodes(), other.nodes()
+	for i := 0; i < len(nodes) && i < len(otherNodes); i++ {
+		if result := nodes[i].compareNode(otherNodes[i]); result != 0 {
+			return result
+		}
+	}
+	return len(nodes) - len(otherNodes)
+}
+
+func (p *Path) compareNode(other *Path) int {
+	if p.Kind != other.Kind {
+		return int(p.Kind) - int(other.Kind)
+	}
+	switch p.Kind {
+	case PathKinField:
+		return strings.Compare(p.Name, other.Name)
+	case PathKindIndex:
+		if !p.IsKeyed() || !other.IsKeyed() {
+			return p.Index - other.Index
+		}
+		if result := strings.Compare(p.IndexBy, other.IndexBy); result != 0 {
+			return result
+		}
+	}
+	key, otherKey := reflect.ValueOf(p.Key), reflect.ValueOf(other.Key)
+	return compareBool(lessSortKey(otherKey, key), lessSortKey(key, otherKey))
+}
+
+func compareBool(x, y bool) int {
+	switch {
+	case x == y:
+		return 0
+	case x:
+		return 1
+	}
+	return -1
+}
+
+func (p *Path) depth() int {
+	depth := 0
+	for node := p; node != nil && node.Kind != PathKindRoot; node = node.Path {
+		depth++
+	}
+	return depth
+}
+
+//compareElement compares element paths by depth and then by index
+func (p *Path) compareElement(other *Path) int {
+	if depth, otherDepth := p.depth(), other.depth(); depth != otherDepth {
+		return depth - otherDepth
+	}
+	return p.Index - other.Index
+}
@@ -0,0 +1,143 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	var testCases = []struct {
+		description string
+		path        *Path
+		expect      string
+		expectErr   bool
+	}{
+		{
+			description: "root",
+			path:        &Path{},
+			expect:      "",
+		},
+		{
+			description: "fields and index",
+			path:        (&Path{}).Field("Dep").Field("Flags").Element(1).Field("Value"),
+			expect:      "Dep.Flags[1].Value",
+		},
+		{
+			description: "map entry",
+			path:        (&Path{}).Field("Attrs").Entry("key").Field("Name"),
+			expect:      "Attrs[key].Name",
+		},
+		{
+			description: "root entry",
+			path:        (&Path{}).Entry("key").Element(2),
+			expect:      "[key][2]",
+		},
+		{
+			description: "escaped entry",
+			path:        (&Path{}).Field("Attrs").Entry(`a.b[c]\d`),
+			expect:      `Attrs[a\.b\[c\]\\d]`,
+		},
+		{
+			description: "escaped field",
+			path:        (&Path{}).Field("a.b").Field("c"),
+			expect:      `a\.b.c`,
+		},
+		{
+			description: "index with leading zeros",
+			path:        &Path{Kind: PathKindIndex, Index: 1234, Key: "01234", Path: (&Path{}).Field("Attrs")},
+			expect:      "Attrs[01234]",
+		},
+		{
+			description: "unterminated bracket",
+			expect:      "Attrs[abc",
+			expectErr:   true,
+		},
+		{
+			description: "empty field",
+			expect:      "Attrs..Name",
+			expectErr:   true,
+		},
+		{
+			description: "unexpected field",
+			expect:      "Attrs[1]Name",
+			expectErr:   true,
+		},
+		{
+			description: "unterminated escape",
+			expect:      `Attrs\`,
+			expectErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParsePath(testCase.expect)
+		if testCase.expectErr {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, testCase.path.String(), testCase.description)
+		assert.EqualValues(t, testCase.path, actual, testCase.description)
+	}
+}
+
+func TestPath_GetSet(t *testing.T) {
+
+	type Flag struct {
+		Value int
+	}
+	type Record struct {
+		ID    int
+		Name  string `diff:"name=title"`
+		Dep   *Record
+		Flags []Flag
+		Attrs map[string]interface{}
+		Codes map[int]string
+	}
+
+	var testCases = []struct {
+		description string
+		path        string
+		value       interface{}
+		expect      interface{}
+	}{
+		{description: "field", path: "ID", value: 10, expect: 10},
+		{description: "renamed field", path: "title", value: "abc", expect: "abc"},
+		{description: "nested pointer", path: "Dep.Dep.ID", value: 3, expect: 3},
+		{description: "slice element", path: "Dep.Flags[1].Value", value: 5, expect: 5},
+		{description: "map entry", path: "Attrs[k.1]", value: "v1", expect: "v1"},
+		{description: "numeric map key", path: "Codes[12]", value: "x", expect: "x"},
+		{description: "numeric string map key", path: "Attrs[12]", value: "y", expect: "y"},
+		{description: "numeric map key with leading zeros", path: "Codes[012]", value: "z", expect: "z"},
+		{description: "converted value", path: "ID", value: 12.0, expect: 12},
+	}
+
+	for _, testCase := range testCases {
+		record := &Record{}
+		path, err := ParsePath(testCase.path)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if !assert.Nil(t, path.Set(record, testCase.value), testCase.description) {
+			continue
+		}
+		actual, err := path.Get(record)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	path, err := ParsePath("Attrs[01234]")
+	assert.Nil(t, err)
+	actual, err := path.Get(&Record{Attrs: map[string]interface{}{"1234": 1, "01234": 2}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, actual)
+
+	_, err = (&Path{}).Field("Unknown").Get(&Record{})
+	assert.NotNil(t, err)
+	_, err = (&Path{}).Field("Flags").Element(3).Get(&Record{})
+	assert.NotNil(t, err)
+}
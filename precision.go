@@ -0,0 +1,45 @@
+package godiff
+
+import (
+	"math"
+	"strconv"
+)
+
+//precision returns tag precision, or config precision if tag does not define one
+func (c *Config) precision(tag *Tag) *int {
+	if tag != nil && tag.Precision != nil {
+		return tag.Precision
+	}
+	return c.Precision
+}
+
+//matchesWithPrecision returns true if float (or numeric decoded string) values are equal when rounded to precision decimal places,
+//ok is false if values are not floats, strings are parsed only when decoded from pair or item separated text
+func matchesWithPrecision(from, to interface{}, precision int, decoded bool) (matched bool, ok bool) {
+	fromFloat, ok := asFloat(from, decoded)
+	if !ok {
+		return false, false
+	}
+	toFloat, ok := asFloat(to, decoded)
+	if !ok {
+		return false, false
+	}
+	pow := math.Pow10(precision)
+	return math.Round(fromFloat*pow) == math.Round(toFloat*pow), true
+}
+
+func asFloat(value interface{}, decoded bool) (float64, bool) {
+	switch actual := value.(type) {
+	case float64:
+		return actual, true
+	case float32:
+		return float64(actual), true
+	case string:
+		if !decoded {
+			return 0, false
+		}
+		result, err := strconv.ParseFloat(actual, 64)
+		return result, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,99 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_Precision(t *testing.T) {
+
+	type Price struct {
+		ID     int
+		Amount float64     `diff:"precision=2"`
+		Rate   float32     `diff:"precision=3"`
+		Prices []float64   `diff:"precision=2"`
+		Value  interface{} `diff:"precision=2"`
+		Attrs  string      `diff:"pairDelimiter=;,pairSeparator=:,precision=2"`
+		Levels string      `diff:"itemSeparator=;,precision=2"`
+		Code   string      `diff:"precision=2"`
+		Total  float64
+	}
+
+	one, two := 0.1, 0.2
+	sum := one + two //0.30000000000000004
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		options     []ConfigOption
+		expect      *ChangeLog
+	}{
+		{
+			description: "tag precision - no changes",
+			from:        Price{ID: 1, Amount: sum, Rate: 1.0001, Prices: []float64{sum}, Value: sum, Attrs: "a:0.3000001", Total: 1},
+			to:          Price{ID: 1, Amount: 0.3, Rate: 1.0002, Prices: []float64{0.3}, Value: 0.3, Attrs: "a:0.30", Total: 1},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "tag precision - decoded values",
+			from:        Price{Attrs: "a:1.10", Levels: "1.001;2"},
+			to:          Price{Attrs: "a:1.1", Levels: "1.002;2.00"},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "tag precision - string values",
+			from:        Price{Code: "1.10"},
+			to:          Price{Code: "1.1"},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Code"), From: "1.10", To: "1.1"},
+			}},
+		},
+		{
+			description: "tag precision - changes",
+			from:        Price{ID: 1, Amount: 0.1, Rate: 1.001, Prices: []float64{0.1}, Value: 0.1, Attrs: "a:0.1", Total: sum},
+			to:          Price{ID: 1, Amount: 0.2, Rate: 1.002, Prices: []float64{0.2}, Value: 0.2, Attrs: "a:0.2", Total: 0.3},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Amount"), From: 0.1, To: 0.2},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Rate"), From: float32(1.001), To: float32(1.002)},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Prices").Element(0), From: 0.1, To: 0.2},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Value"), From: 0.1, To: 0.2},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Attrs").Entry("a"), From: "0.1", To: "0.2"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Total"), From: sum, To: 0.3},
+			}},
+		},
+		{
+			description: "config precision",
+			from:        map[string]interface{}{"a": sum},
+			to:          map[string]interface{}{"a": 0.3},
+			options:     []ConfigOption{WithPrecision(4)},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "config precision - string values",
+			from:        map[string]string{"a": "007", "b": "1.10"},
+			to:          map[string]string{"a": "7", "b": "1.1"},
+			options:     []ConfigOption{WithPrecision(2)},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Entry("a"), From: "007", To: "7"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Entry("b"), From: "1.10", To: "1.1"},
+			}},
+		},
+		{
+			description: "config precision overridden by tag",
+			from:        Price{Amount: 1.001, Total: 1.001},
+			to:          Price{Amount: 1.002, Total: 1.002},
+			options:     []ConfigOption{WithPrecision(1)},
+			expect:      &ChangeLog{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to), testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+}
@@ -9,11 +9,12 @@ import (
 type Registry struct {
 	sync.RWMutex
 	differs map[reflect.Type]map[reflect.Type]*Differ
+	equals  map[reflect.Type]*equalComparator
 }
 
 func (r *Registry) Get(from, to reflect.Type, tag *Tag, options ...ConfigOption) (*Differ, error) {
 	if tag != nil && (tag.PairSeparator != "" || tag.ItemSeparator != "") {
-		return New(from, to, WithRegistry(r), WithTag(tag))
+		return New(from, to, append(options, WithRegistry(r), WithTag(tag))...)
 	}
 	fromDiffers := r.getFromDiffers(from)
 	r.RWMutex.RLock()
@@ -47,6 +48,24 @@ func (r *Registry) getFromDiffers(from reflect.Type) map[reflect.Type]*Differ {
 	return fromDiffers
 }
 
+//equalComparator returns cached comparator for type defining Equal method, or nil
+func (r *Registry) equalComparator(t reflect.Type) *equalComparator {
+	r.RWMutex.RLock()
+	comparator, ok := r.equals[t]
+	r.RWMutex.RUnlock()
+	if ok {
+		return comparator
+	}
+	comparator = newEqualComparator(t)
+	r.RWMutex.Lock()
+	if r.equals == nil {
+		r.equals = make(map[reflect.Type]*equalComparator)
+	}
+	r.equals[t] = comparator
+	r.RWMutex.Unlock()
+	return comparator
+}
+
 func NewRegistry() *Registry {
 	return &Registry{differs: map[reflect.Type]map[reflect.Type]*Differ{}}
 }
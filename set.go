@@ -0,0 +1,28 @@
+package godiff
+
+//multiset matches elements regardless of their positions, each 'to' element is matched with the first unmatched equal
+//'from' element, unmatched 'from' positions are returned as removed, unmatched 'to' positions as added
+func multiset(fromLen, toLen int, equal func(i, j int) (bool, error)) (removed []int, added []int, err error) {
+	matched := make([]bool, fromLen)
+	for j := 0; j < toLen; j++ {
+		found := false
+		for i := 0; i < fromLen && !found; i++ {
+			if matched[i] {
+				continue
+			}
+			if found, err = equal(i, j); err != nil {
+				return nil, nil, err
+			}
+			matched[i] = found
+		}
+		if !found {
+			added = append(added, j)
+		}
+	}
+	for i, ok := range matched {
+		if !ok {
+			removed = append(removed, i)
+		}
+	}
+	return removed, added, nil
+}
@@ -0,0 +1,81 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_Set(t *testing.T) {
+
+	type Role struct {
+		Name  string
+		Scope string
+	}
+	type Account struct {
+		ID          int
+		Tags        []string      `diff:"set=true"`
+		Roles       []Role        `diff:"set=true"`
+		Permissions []interface{} `diff:"set=true"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Account
+		to          *Account
+		expect      *ChangeLog
+	}{
+		{
+			description: "reordered set",
+			from:        &Account{Tags: []string{"a", "b", "c"}},
+			to:          &Account{Tags: []string{"c", "a", "b"}},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "multiset counts",
+			from:        &Account{Tags: []string{"a", "b", "a", "a"}},
+			to:          &Account{Tags: []string{"b", "a", "c", "c"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Tags").Element(3), From: "a"},
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Tags").Element(2), From: "a"},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Tags").Element(2), To: "c"},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Tags").Element(3), To: "c"},
+			}},
+		},
+		{
+			description: "struct elements",
+			from:        &Account{Roles: []Role{{"admin", "x"}, {"reader", "y"}}},
+			to:          &Account{Roles: []Role{{"reader", "y"}, {"admin", "z"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Roles").Element(0), From: Role{"admin", "x"}},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Roles").Element(1), To: Role{"admin", "z"}},
+			}},
+		},
+		{
+			description: "interface elements",
+			from:        &Account{Permissions: []interface{}{"read", 1, "write"}},
+			to:          &Account{Permissions: []interface{}{1, "write", "exec"}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeDelete, Path: (&Path{}).Field("Permissions").Element(0), From: "read"},
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Permissions").Element(2), To: "exec"},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Account{}), reflect.TypeOf(&Account{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		if !assert.EqualValues(t, testCase.expect, changeLog, testCase.description) {
+			continue
+		}
+		if !assert.Nil(t, changeLog.Apply(testCase.from), testCase.description) {
+			continue
+		}
+		assert.ElementsMatch(t, testCase.to.Tags, testCase.from.Tags, testCase.description)
+		assert.ElementsMatch(t, testCase.to.Roles, testCase.from.Roles, testCase.description)
+		assert.ElementsMatch(t, testCase.to.Permissions, testCase.from.Permissions, testCase.description)
+	}
+}
@@ -3,6 +3,7 @@ package godiff
 import (
 	"github.com/viant/xunsafe"
 	"reflect"
+	"sort"
 	"unsafe"
 )
 
@@ -19,205 +20,282 @@ type sliceDiffer struct {
 
 func (s *sliceDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{}, changeType ChangeType, options *Options) error {
 	if s.isInterface {
-		return s.diffIfacedSlice(changeLog, path, from, to, changeType, options)
+		return s.diffIfacedSlice(changeLog, path, from, to, options)
 
 	}
-	return s.diffTypedSlice(changeLog, path, from, to, changeType, options)
+	return s.diffTypedSlice(changeLog, path, from, to, options)
 }
 
-func (s *sliceDiffer) diffTypedSlice(changeLog *ChangeLog, path *Path, from interface{}, to interface{}, changeType ChangeType, options *Options) error {
+func (s *sliceDiffer) diffTypedSlice(changeLog *ChangeLog, path *Path, from interface{}, to interface{}, options *Options) error {
 	fromPtr := xunsafe.AsPointer(from)
 	toPtr := xunsafe.AsPointer(to)
 
 	if s.tag.Sort {
-		if s.itemDiffer == nil {
-			from = sortPrimitive(from)
-			fromPtr = xunsafe.AsPointer(from)
-			to = sortPrimitive(to)
-			toPtr = xunsafe.AsPointer(to)
+		var err error
+		if from, err = sortSlice(from, s.tag.SortBy, s.tag.SortDesc, s.config.TagName); err == nil {
+			to, err = sortSlice(to, s.tag.SortBy, s.tag.SortDesc, s.config.TagName)
 		}
+		if err != nil {
+			changeLog.AddError(path, err)
+			return nil
+		}
+		fromPtr = xunsafe.AsPointer(from)
+		toPtr = xunsafe.AsPointer(to)
 
 	}
-	changeType = ChangeTypeUpdate
 	var fromLen, toLen int
 	if from != nil {
-		if fromLen = s.fromSlice.Len(fromPtr); fromLen == 0 {
-			changeType = ChangeTypeCreate
-		}
+		fromLen = s.fromSlice.Len(fromPtr)
 	}
 	if to != nil {
-		if toLen = s.toSlice.Len(toPtr); toLen == 0 && fromLen > 0 {
-			changeType = ChangeTypeDelete
-		}
+		toLen = s.toSlice.Len(toPtr)
 	}
 
-	if by := s.tag.IndexBy; by != "" && fromLen > 0 && toLen > 0 {
-		fromIndex := s.fromIndexer.indexBy(s.fromSlice, fromPtr, by)
-		toIndex := s.toIndexer.indexBy(s.toSlice, toPtr, by)
-		return s.diffIndexedElement(changeLog, path, fromIndex, toIndex, changeType, options)
+	if s.tag.Set {
+		fromAt := func(i int) interface{} { return s.fromSlice.ValueAt(fromPtr, i) }
+		toAt := func(i int) interface{} { return s.toSlice.ValueAt(toPtr, i) }
+		return s.diffSetElements(changeLog, path, fromLen, toLen, fromAt, toAt, func(changeLog *ChangeLog, index int, from, to interface{}) error {
+			return s.diffElement(changeLog, path.Element(index), from, to, ChangeTypeUpdate, options)
+		})
+	}
+	if by := s.tag.IndexBy; by != "" {
+		fromIndex, err := s.fromIndexer.indexBy(s.fromSlice, fromPtr, by)
+		if err != nil {
+			changeLog.AddError(path, err)
+			return nil
+		}
+		toIndex, err := s.toIndexer.indexBy(s.toSlice, toPtr, by)
+		if err != nil {
+			changeLog.AddError(path, err)
+			return nil
+		}
+		return s.diffIndexedElement(changeLog, path, fromIndex, toIndex, options)
+	}
+	if s.tag.Align == AlignLCS {
+		fromAt := func(i int) interface{} { return s.fromSlice.ValueAt(fromPtr, i) }
+		toAt := func(i int) interface{} { return s.toSlice.ValueAt(toPtr, i) }
+		return s.diffAlignedElements(changeLog, path, fromLen, toLen, fromAt, toAt, func(changeLog *ChangeLog, index int, from, to interface{}) error {
+			return s.diffElement(changeLog, path.Element(index), from, to, ChangeTypeUpdate, options)
+		}, options)
 	}
 
-	return s.diffSliceElements(changeLog, path, changeType, fromPtr, toPtr, fromLen, toLen, options)
+	return s.diffSliceElements(changeLog, path, fromPtr, toPtr, fromLen, toLen, options)
 }
 
-func (s *sliceDiffer) diffSliceElements(changeLog *ChangeLog, path *Path, changeType ChangeType, fromPtr, toPtr unsafe.Pointer, fromLen int, toLen int, options *Options) error {
-	var repeat int
-	if repeat = fromLen; repeat < toLen {
-		repeat = toLen
+func (s *sliceDiffer) diffSliceElements(changeLog *ChangeLog, path *Path, fromPtr, toPtr unsafe.Pointer, fromLen int, toLen int, options *Options) error {
+	common := fromLen
+	if common > toLen {
+		common = toLen
 	}
-	var err error
-	for i := 0; i < repeat; i++ {
-		switch changeType {
-		case ChangeTypeCreate:
-			value := s.toSlice.ValueAt(toPtr, i)
-			if s.itemDiffer != nil {
-				if err = s.itemDiffer.diff(changeLog, path.Element(i), nil, value, changeType, options); err != nil {
-					return err
-				}
-				continue
-			}
-			changeLog.AddCreate(path.Element(i), value)
-
-		case ChangeTypeDelete:
-			value := s.fromSlice.ValueAt(fromPtr, i)
-			if s.itemDiffer != nil {
-				if err = s.itemDiffer.diff(changeLog, path.Element(i), value, nil, changeType, options); err != nil {
-					return err
-				}
-				continue
-			}
-			changeLog.AddCreate(path.Element(i), value)
-		case ChangeTypeUpdate:
-			if fromLen <= i {
-				value := s.toSlice.ValueAt(toPtr, i)
-				if s.itemDiffer != nil {
-					if err = s.itemDiffer.diff(changeLog, path.Element(i), nil, value, ChangeTypeCreate, options); err != nil {
-						return err
-					}
-					continue
-				}
-				changeLog.AddCreate(path.Element(i), value)
-				continue
-			} else if toLen <= i {
-				value := s.fromSlice.ValueAt(fromPtr, i)
-				if s.itemDiffer != nil {
-					if err = s.itemDiffer.diff(changeLog, path.Element(i), value, nil, ChangeTypeDelete, options); err != nil {
-						return err
-					}
-					continue
-				}
-				changeLog.AddCreate(path.Element(i), value)
-				continue
-			}
+	for i := 0; i < common; i++ {
+		fromItem := s.fromSlice.ValueAt(fromPtr, i)
+		toItem := s.toSlice.ValueAt(toPtr, i)
+		if err := s.diffElement(changeLog, path.Element(i), fromItem, toItem, ChangeTypeUpdate, options); err != nil {
+			return err
+		}
+	}
+	for i := common; i < toLen; i++ {
+		if err := s.diffElement(changeLog, path.Element(i), nil, s.toSlice.ValueAt(toPtr, i), ChangeTypeCreate, options); err != nil {
+			return err
+		}
+	}
+	//removed elements go in descending order, so that change log can be applied in sequence
+	for i := fromLen - 1; i >= common; i-- {
+		if err := s.diffElement(changeLog, path.Element(i), s.fromSlice.ValueAt(fromPtr, i), nil, ChangeTypeDelete, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			fromItem := s.fromSlice.ValueAt(fromPtr, i)
-			toItem := s.toSlice.ValueAt(toPtr, i)
-			if s.itemDiffer != nil {
-				if err = s.itemDiffer.diff(changeLog, path.Element(i), fromItem, toItem, ChangeTypeUpdate, options); err != nil {
-					return err
-				}
-				continue
-			}
-			if !matches(fromItem, toItem) {
-				changeLog.AddUpdate(path.Element(i), fromItem, toItem)
-			}
+func (s *sliceDiffer) diffElement(changeLog *ChangeLog, path *Path, from, to interface{}, changeType ChangeType, options *Options) error {
+	if s.itemDiffer != nil {
+		return s.itemDiffer.diff(changeLog, path, from, to, changeType, options)
+	}
+	switch changeType {
+	case ChangeTypeCreate:
+		changeLog.addValue(ChangeTypeCreate, path, nil, to, s.config.timeLayout(s.tag))
+	case ChangeTypeDelete:
+		changeLog.addValue(ChangeTypeDelete, path, from, nil, s.config.timeLayout(s.tag))
+	default:
+		matched, err := s.config.matches(from, to, s.tag)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			changeLog.addValue(ChangeTypeUpdate, path, from, to, s.config.timeLayout(s.tag))
 		}
 	}
 	return nil
 }
 
-func (s *sliceDiffer) diffIndexedElement(changeLog *ChangeLog, path *Path, fromIndex map[interface{}]*entry, toIndex map[interface{}]*entry, changeType ChangeType, options *Options) error {
-	for k := range fromIndex {
-		fromValue := fromIndex[k]
-		toValue, ok := toIndex[k]
+//diffIndexedElement compares elements matched by index key, element changes use keyed element paths with from and to positions
+func (s *sliceDiffer) diffIndexedElement(changeLog *ChangeLog, path *Path, fromIndex map[interface{}]*entry, toIndex map[interface{}]*entry, options *Options) error {
+	by := s.tag.IndexBy
+	var removed []*entry
+	var kept [][2]int
+	var keptPaths []*Path
+	for _, fromValue := range sortedEntries(fromIndex) {
+		toValue, ok := toIndex[fromValue.key]
 		if !ok {
-			changeLog.AddDelete(path.Element(fromValue.index), fromValue.value)
+			removed = append(removed, fromValue)
 			continue
 		}
-		if s.itemDiffer != nil {
-			if err := s.itemDiffer.diff(changeLog, path, fromValue.value, toValue.value, ChangeTypeUpdate, options); err != nil {
-				return err
-			}
-			continue
+		elementPath := path.KeyedElement(by, fromValue.key, fromValue.index, toValue.index)
+		kept = append(kept, [2]int{fromValue.index, toValue.index})
+		keptPaths = append(keptPaths, elementPath)
+		if err := s.diffElement(changeLog, elementPath, fromValue.value, toValue.value, ChangeTypeUpdate, options); err != nil {
+			return err
 		}
-		if !matches(fromValue.value, toValue.value) {
-			changeLog.AddUpdate(path.Element(fromValue.index), fromValue.value, toValue.value)
+	}
+	for i := len(removed) - 1; i >= 0; i-- {
+		changeLog.AddDelete(path.KeyedElement(by, removed[i].key, removed[i].index, -1), removed[i].value)
+	}
+	if options.detectMoves {
+		s.addMoves(changeLog, path, kept, nil, keptPaths)
+	}
+	for _, toValue := range sortedEntries(toIndex) {
+		if _, ok := fromIndex[toValue.key]; ok {
+			continue
 		}
+		changeLog.AddCreate(path.KeyedElement(by, toValue.key, -1, toValue.index), toValue.value)
 	}
+	return nil
+}
 
-	for k := range toIndex {
-		if _, ok := fromIndex[k]; ok {
+//addMoves adds moves of kept elements (from, to positions) in 'to' order, if moved flags are not specified,
+//elements outside the longest common subsequence of kept elements are moved, keyed element paths (if specified) are used for move changes
+func (s *sliceDiffer) addMoves(changeLog *ChangeLog, path *Path, kept [][2]int, moved []bool, keptPaths []*Path) {
+	if moved == nil {
+		byTo := make([][2]int, len(kept))
+		copy(byTo, kept)
+		sort.Slice(byTo, func(i, j int) bool { return byTo[i][1] < byTo[j][1] })
+		stable, _ := lcs(len(kept), len(byTo), func(i, j int) (bool, error) {
+			return kept[i] == byTo[j], nil
+		})
+		moved = make([]bool, len(kept))
+		for i := range moved {
+			moved[i] = true
+		}
+		for _, pair := range stable {
+			moved[pair[0]] = false
+		}
+	}
+	byTo := make([]int, 0, len(kept))
+	for k := range kept {
+		if moved[k] {
+			byTo = append(byTo, k)
+		}
+	}
+	sort.Slice(byTo, func(i, j int) bool { return kept[byTo[i]][1] < kept[byTo[j]][1] })
+	for _, k := range byTo {
+		if keptPaths == nil {
+			changeLog.AddMove(path, kept[k][0], kept[k][1])
 			continue
 		}
-		toValue := toIndex[k]
-		changeLog.AddCreate(path.Element(toValue.index), toValue.value)
+		changeLog.Add(&Change{Type: ChangeTypeMove, Path: keptPaths[k], From: kept[k][0], To: kept[k][1]})
+	}
+}
+
+//diffAlignedElements compares elements aligned by longest common subsequence, elements between aligned ones are compared first
+//(with 'from' positions), then removed elements are reported in descending order, followed by added elements (with 'to' positions)
+func (s *sliceDiffer) diffAlignedElements(changeLog *ChangeLog, path *Path, fromLen, toLen int, fromAt, toAt func(i int) interface{}, diffElement func(changeLog *ChangeLog, index int, from, to interface{}) error, options *Options) error {
+	alignment, err := align(fromLen, toLen, func(i, j int) (bool, error) {
+		elementLog := &ChangeLog{}
+		if err := diffElement(elementLog, i, fromAt(i), toAt(j)); err != nil {
+			return false, err
+		}
+		return elementLog.Size() == 0, nil
+	}, options.detectMoves)
+	if err != nil {
+		return err
+	}
+	for _, pair := range alignment.updated {
+		if err = diffElement(changeLog, pair[0], fromAt(pair[0]), toAt(pair[1])); err != nil {
+			return err
+		}
+	}
+	for i := len(alignment.deleted) - 1; i >= 0; i-- {
+		index := alignment.deleted[i]
+		changeLog.AddDelete(path.Element(index), fromAt(index))
+	}
+	if len(alignment.moved) > 0 {
+		kept := append(append(append([][2]int{}, alignment.matched...), alignment.updated...), alignment.moved...)
+		moved := make([]bool, len(kept))
+		for i := len(kept) - len(alignment.moved); i < len(kept); i++ {
+			moved[i] = true
+		}
+		s.addMoves(changeLog, path, kept, moved, nil)
+	}
+	for _, index := range alignment.created {
+		changeLog.AddCreate(path.Element(index), toAt(index))
 	}
 	return nil
 }
 
-func (s *sliceDiffer) diffIfacedSlice(changeLog *ChangeLog, path *Path, from interface{}, to interface{}, changeType ChangeType, options *Options) error {
-	var repeat int
+//diffSetElements compares slices as multisets, removed elements are reported in descending order ('from' positions),
+//followed by added elements ('to' positions), each duplicate occurrence is reported separately
+func (s *sliceDiffer) diffSetElements(changeLog *ChangeLog, path *Path, fromLen, toLen int, fromAt, toAt func(i int) interface{}, diffElement func(changeLog *ChangeLog, index int, from, to interface{}) error) error {
+	removed, added, err := multiset(fromLen, toLen, func(i, j int) (bool, error) {
+		elementLog := &ChangeLog{}
+		if err := diffElement(elementLog, i, fromAt(i), toAt(j)); err != nil {
+			return false, err
+		}
+		return elementLog.Size() == 0, nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(removed) - 1; i >= 0; i-- {
+		changeLog.AddDelete(path.Element(removed[i]), fromAt(removed[i]))
+	}
+	for _, index := range added {
+		changeLog.AddCreate(path.Element(index), toAt(index))
+	}
+	return nil
+}
 
-	changeType = ChangeTypeUpdate
+func (s *sliceDiffer) diffIfacedSlice(changeLog *ChangeLog, path *Path, from interface{}, to interface{}, options *Options) error {
+	var fromLen, toLen int
 	fromPtr := xunsafe.AsPointer(from)
-	var fromLen = -1
 	if from != nil {
 		fromLen = s.fromSlice.Len(fromPtr)
-	} else {
-		changeType = ChangeTypeCreate
 	}
-
-	toLen := -1
 	toPtr := xunsafe.AsPointer(to)
 	if to != nil {
-		toLen = s.fromSlice.Len(toPtr)
-	} else {
-		changeType = ChangeTypeDelete
+		toLen = s.toSlice.Len(toPtr)
 	}
-
-	if repeat = fromLen; repeat < toLen {
-		repeat = toLen
+	if s.tag.Set || s.tag.Align == AlignLCS {
+		fromAt := func(i int) interface{} { return s.fromSlice.ValueAt(fromPtr, i) }
+		toAt := func(i int) interface{} { return s.toSlice.ValueAt(toPtr, i) }
+		diffElement := func(changeLog *ChangeLog, index int, from, to interface{}) error {
+			return s.diffIfaceElement(changeLog, path, from, to, index, ChangeTypeUpdate, options)
+		}
+		if s.tag.Set {
+			return s.diffSetElements(changeLog, path, fromLen, toLen, fromAt, toAt, diffElement)
+		}
+		return s.diffAlignedElements(changeLog, path, fromLen, toLen, fromAt, toAt, diffElement, options)
+	}
+	common := fromLen
+	if common > toLen {
+		common = toLen
 	}
-
 	var err error
-	for i := 0; i < repeat; i++ {
-		switch changeType {
-		case ChangeTypeCreate:
-			value := s.toSlice.ValueAt(toPtr, i)
-			if err = s.diffIfaceElement(changeLog, path, nil, value, i, changeType, options); err != nil {
-				return err
-			}
-			continue
-
-		case ChangeTypeDelete:
-			value := s.fromSlice.ValueAt(fromPtr, i)
-			if err = s.diffIfaceElement(changeLog, path, value, nil, i, changeType, options); err != nil {
-				return err
-			}
-		case ChangeTypeUpdate:
-
-			if i < fromLen && i >= toLen {
-				value := s.toSlice.ValueAt(toPtr, i)
-				if err = s.diffIfaceElement(changeLog, path, nil, value, i, ChangeTypeCreate, options); err != nil {
-					return err
-				}
-				continue
-			}
-
-			if i < toLen && i >= fromLen {
-				value := s.toSlice.ValueAt(toPtr, i)
-				if err = s.diffIfaceElement(changeLog, path, value, nil, i, ChangeTypeDelete, options); err != nil {
-					return err
-				}
-				continue
-			}
-
-			fromItem := s.fromSlice.ValueAt(fromPtr, i)
-			toItem := s.toSlice.ValueAt(toPtr, i)
-			if err = s.diffIfaceElement(changeLog, path, fromItem, toItem, i, ChangeTypeUpdate, options); err != nil {
-				return err
-			}
-			continue
+	for i := 0; i < common; i++ {
+		fromItem := s.fromSlice.ValueAt(fromPtr, i)
+		toItem := s.toSlice.ValueAt(toPtr, i)
+		if err = s.diffIfaceElement(changeLog, path, fromItem, toItem, i, ChangeTypeUpdate, options); err != nil {
+			return err
+		}
+	}
+	for i := common; i < toLen; i++ {
+		if err = s.diffIfaceElement(changeLog, path, nil, s.toSlice.ValueAt(toPtr, i), i, ChangeTypeCreate, options); err != nil {
+			return err
+		}
+	}
+	for i := fromLen - 1; i >= common; i-- {
+		if err = s.diffIfaceElement(changeLog, path, s.fromSlice.ValueAt(fromPtr, i), nil, i, ChangeTypeDelete, options); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -245,10 +323,10 @@ func (s *sliceDiffer) diffIfaceElement(changeLog *ChangeLog, path *Path, from, t
 	}
 	if toValue.Kind() == reflect.Ptr {
 		toValue = toValue.Elem()
-		to = toValue.Elem()
+		to = toValue.Interface()
 	}
 
-	itemDiffer, err := s.config.registry.Get(fromValue.Type(), toValue.Type(), s.tag)
+	itemDiffer, err := s.config.registry.Get(fromValue.Type(), toValue.Type(), s.tag, WithConfig(s.config))
 	if err != nil {
 		return err
 	}
@@ -264,6 +342,8 @@ func newSliceDiffer(from, to reflect.Type, config *Config, tag *Tag) (*sliceDiff
 		fromSlice: xunsafe.NewSlice(from),
 		tag:       tag,
 	}
+	result.fromIndexer.tagName = config.TagName
+	result.toIndexer.tagName = config.TagName
 
 	result.toSlice = result.fromSlice
 	if from != to {
@@ -275,6 +355,9 @@ func newSliceDiffer(from, to reflect.Type, config *Config, tag *Tag) (*sliceDiff
 		return result, nil
 	}
 
+	if config.isLeaf(from.Elem()) {
+		return result, nil
+	}
 	fromElem := structType(from.Elem())
 	toElem := structType(to.Elem())
 	if fromElem != nil {
@@ -282,7 +365,19 @@ func newSliceDiffer(from, to reflect.Type, config *Config, tag *Tag) (*sliceDiff
 		if err != nil {
 			return nil, err
 		}
-		result.itemDiffer = &Differ{structDiffer: differ}
+		result.itemDiffer = &Differ{config: config, structDiffer: differ}
+	} else if mapType(from.Elem()) != nil && mapType(to.Elem()) != nil {
+		differ, err := newMapDiffer(from.Elem(), to.Elem(), config, tag)
+		if err != nil {
+			return nil, err
+		}
+		result.itemDiffer = &Differ{config: config, mapDiffer: differ}
+	} else if arrayType(from.Elem()) != nil && arrayType(to.Elem()) != nil {
+		differ, err := newArrayDiffer(from.Elem(), to.Elem(), config, tag)
+		if err != nil {
+			return nil, err
+		}
+		result.itemDiffer = differ
 	}
 	return result, nil
 }
@@ -1,31 +1,72 @@
 package godiff
 
-import "sort"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
 
-func sortPrimitive(value interface{}) interface{} {
-	switch actual := value.(type) {
-	case *[]int:
-		clone := make([]int, len(*actual))
-		copy(clone, *actual)
-		sort.Ints(clone)
-		return &clone
-	case *[]string:
-		clone := make([]string, len(*actual))
-		copy(clone, *actual)
-		sort.Strings(clone)
-		return &clone
-	case *[]float64:
-		clone := make([]float64, len(*actual))
-		copy(clone, *actual)
-		sort.Float64s(clone)
-		return &clone
-	case *[]float32:
-		clone := make([]float32, len(*actual))
-		copy(clone, *actual)
-		sort.SliceStable(clone, func(i, j int) bool {
-			return clone[i] < clone[j]
-		})
-		return &clone
+//sortSlice returns sorted slice pointer copy, elements are sorted by value or by field (dotted path) if specified
+func sortSlice(value interface{}, by string, desc bool, tagName string) (interface{}, error) {
+	slicePtr := reflect.ValueOf(value)
+	if value == nil || slicePtr.Kind() != reflect.Ptr || slicePtr.IsNil() || slicePtr.Elem().Kind() != reflect.Slice {
+		return value, nil
 	}
-	return value
+	slice := slicePtr.Elem()
+	keys := make([]reflect.Value, slice.Len())
+	for i := range keys {
+		item := slice.Index(i)
+		if by != "" {
+			key, err := indexValue(item, by, tagName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sort %v: %w", by, err)
+			}
+			keys[i] = reflect.ValueOf(key)
+		} else {
+			keys[i] = indirect(item)
+		}
+		if keys[i].IsValid() && !isSortableKind(keys[i].Kind()) {
+			return nil, fmt.Errorf("unsupported sort key type: %s", keys[i].Type().String())
+		}
+	}
+	positions := make([]int, len(keys))
+	for i := range positions {
+		positions[i] = i
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		x, y := keys[positions[i]], keys[positions[j]]
+		if desc {
+			x, y = y, x
+		}
+		return lessSortKey(x, y)
+	})
+	result := reflect.New(slice.Type())
+	sorted := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len())
+	for i, position := range positions {
+		sorted.Index(i).Set(slice.Index(position))
+	}
+	result.Elem().Set(sorted)
+	return result.Interface(), nil
+}
+
+//lessSortKey compares sort keys, nil keys go first
+func lessSortKey(x, y reflect.Value) bool {
+	if !x.IsValid() || !y.IsValid() {
+		return !x.IsValid() && y.IsValid()
+	}
+	if x.Kind() != y.Kind() {
+		return x.Kind() < y.Kind()
+	}
+	return lessKey(x, y)
+}
+
+func isSortableKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,96 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDiffer_Sort(t *testing.T) {
+
+	type Level uint8
+	type Item struct {
+		ID   int
+		Name string
+	}
+	type Holder struct {
+		ID       int
+		Items    []Item   `diff:"sort=ID"`
+		Ptrs     []*Item  `diff:"sort=Name desc"`
+		Levels   []Level  `diff:"sort=true"`
+		Flags    []bool   `diff:"sort=true desc"`
+		Unsorted []Item   `diff:"sort=true"`
+		Invalid  []Item   `diff:"sort=Unknown"`
+		Ints     []uint16 `diff:"sort=true"`
+	}
+
+	var testCases = []struct {
+		description string
+		from        *Holder
+		to          *Holder
+		expect      *ChangeLog
+	}{
+		{
+			description: "struct slice sorted by field",
+			from:        &Holder{Items: []Item{{2, "b"}, {1, "a"}}},
+			to:          &Holder{Items: []Item{{1, "a"}, {2, "x"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Items").Element(1).Field("Name"), From: "b", To: "x"},
+			}},
+		},
+		{
+			description: "pointer slice sorted by field descending",
+			from:        &Holder{Ptrs: []*Item{{1, "a"}, {2, "b"}}},
+			to:          &Holder{Ptrs: []*Item{{2, "b"}, {3, "a"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Ptrs").Element(1).Field("ID"), From: 1, To: 3},
+			}},
+		},
+		{
+			description: "named and unsigned primitives",
+			from:        &Holder{Levels: []Level{3, 1, 2}, Flags: []bool{false, true}, Ints: []uint16{7, 5}},
+			to:          &Holder{Levels: []Level{1, 2, 3}, Flags: []bool{true, false}, Ints: []uint16{5, 7}},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "unsortable element",
+			from:        &Holder{Unsorted: []Item{{1, "a"}}},
+			to:          &Holder{Unsorted: []Item{{2, "a"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Path: (&Path{}).Field("Unsorted"), Error: "unsupported sort key type: godiff.Item"},
+			}},
+		},
+		{
+			description: "unknown sort field",
+			from:        &Holder{Invalid: []Item{{1, "a"}}},
+			to:          &Holder{Invalid: []Item{{2, "a"}}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Path: (&Path{}).Field("Invalid"), Error: "invalid sort Unknown: unknown field Unknown in godiff.Item"},
+			}},
+		},
+	}
+
+	differ, err := New(reflect.TypeOf(&Holder{}), reflect.TypeOf(&Holder{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, testCase := range testCases {
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+}
+
+func TestParseTag_Sort(t *testing.T) {
+	tag, err := ParseTag("sort=Meta.ID desc")
+	if assert.Nil(t, err) {
+		assert.True(t, tag.Sort)
+		assert.EqualValues(t, "Meta.ID", tag.SortBy)
+		assert.True(t, tag.SortDesc)
+	}
+	tag, err = ParseTag("sort=false")
+	if assert.Nil(t, err) {
+		assert.False(t, tag.Sort)
+	}
+	_, err = ParseTag("sort=ID down")
+	assert.NotNil(t, err)
+}
@@ -27,11 +27,11 @@ func (s *structDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface
 
 	for _, field := range s.fields {
 		if fromValue, err = field.from.Value(fromPtr); err != nil {
-			changeLog.AddError(path.Field(field.name), err)
+			changeLog.AddError(field.path(path), err)
 			continue
 		}
 		if toValue, err = field.to.Value(toPtr); err != nil {
-			changeLog.AddError(path.Field(field.name), err)
+			changeLog.AddError(field.path(path), err)
 			continue
 		}
 		if options.setMarker {
@@ -51,22 +51,16 @@ func (s *structDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface
 			continue
 		}
 		if field.differ != nil {
-			diffChangeType := ChangeTypeUpdate
-			if field.Kind == reflect.Slice {
+			diffChangeType := discoverChangeType(fromValue, toValue)
+			if field.Kind == reflect.Slice || field.Kind == reflect.Array {
 				if fromValue != nil {
 					fromValue = field.from.Addr(fromPtr)
-				} else {
-					diffChangeType = ChangeTypeCreate
 				}
 				if toValue != nil {
 					toValue = field.to.Addr(toPtr)
-					diffChangeType = ChangeTypeDelete
 				}
 			}
-			if fromValue == nil {
-				diffChangeType = ChangeTypeCreate
-			}
-			if err = field.differ.diff(changeLog, path.Field(field.name), fromValue, toValue, diffChangeType, options); err != nil {
+			if err = field.differ.diff(changeLog, field.path(path), fromValue, toValue, diffChangeType, options); err != nil {
 				return err
 			}
 			continue
@@ -74,19 +68,39 @@ func (s *structDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface
 
 		switch changeType {
 		case ChangeTypeCreate:
-			if !field.to.IsNil(toPtr) {
-				changeLog.AddCreate(path.Field(field.name), toValue)
+			if !field.to.IsZero(toPtr) {
+				changeLog.addValue(ChangeTypeCreate, field.path(path), nil, toValue, s.config.timeLayout(field.tag))
 				continue
 			}
+			if fromValue == nil {
+				continue //zero value of created struct
+			}
 		case ChangeTypeDelete:
-			if !field.from.IsNil(fromPtr) {
-				changeLog.AddDelete(path.Field(field.name), fromValue)
+			if !field.from.IsZero(fromPtr) {
+				changeLog.addValue(ChangeTypeDelete, field.path(path), fromValue, nil, s.config.timeLayout(field.tag))
 				continue
 			}
+			if toValue == nil {
+				continue //zero value of deleted struct
+			}
+		}
+		compareFrom, compareTo := fromValue, toValue
+		if field.convert {
+			compareFrom, compareTo = convertKinds(fromValue, toValue)
 		}
-		if !matches(fromValue, toValue) {
-			changeLog.AddUpdate(path.Field(field.name), fromValue, toValue)
+		matched, err := s.config.matches(compareFrom, compareTo, field.tag)
+		if err != nil {
+			changeLog.AddError(field.path(path), err)
+			continue
+		}
+		if matched {
+			continue
+		}
+		if field.mismatch != nil {
+			changeLog.AddError(field.path(path), field.mismatch)
+			continue
 		}
+		changeLog.addValue(ChangeTypeUpdate, field.path(path), fromValue, toValue, s.config.timeLayout(field.tag))
 	}
 	return nil
 }
@@ -114,6 +128,9 @@ func (s *structDiffer) matchFields() error {
 			continue
 		}
 		tag.init(s.config)
+		if _, err = s.config.namedComparator(tag); err != nil {
+			return fmt.Errorf("invalid field %v: %w", fromField.Name, err)
+		}
 
 		fromAccessor := newAccessor(i, fromField, tag)
 		toAccessor := fromAccessor
@@ -125,7 +142,21 @@ func (s *structDiffer) matchFields() error {
 			}
 		}
 		aField := newField(fromField, fromAccessor, toAccessor, tag)
+		if !typesMatches {
+			aField.convert, aField.mismatch = kindMismatch(fromField.Type, toAccessor.Type, s.config)
+		}
 		fields = append(fields, aField)
+		if tag.Comparator != "" || s.config.isLeaf(fromField.Type) {
+			continue //values are compared with registered comparator
+		}
+		if !typesMatches && isStructMap(aField.from.Type, aField.to.Type) {
+			differ, err := newStructMapDiffer(aField.from.Type, aField.to.Type, s.config)
+			if err != nil {
+				return err
+			}
+			aField.differ = &Differ{config: s.config, structMapDiffer: differ}
+			continue
+		}
 
 		switch aField.Kind {
 		case reflect.Map:
@@ -151,6 +182,10 @@ func (s *structDiffer) matchFields() error {
 				return err
 			}
 			aField.differ = &Differ{config: s.config, sliceDiffer: differ}
+		case reflect.Array:
+			if aField.differ, err = newArrayDiffer(aField.from.Type, aField.to.Type, s.config, aField.tag); err != nil {
+				return err
+			}
 		case reflect.Interface:
 			differ, err := newIfaceDiffer(s.config, aField.tag)
 			if err != nil {
@@ -0,0 +1,220 @@
+package godiff
+
+import (
+	"fmt"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+type (
+	//structMapDiffer compares struct with map, map keys are matched with struct fields
+	structMapDiffer struct {
+		config     *Config
+		structType reflect.Type
+		mapType    reflect.Type
+		reversed   bool //from is a map, to is a struct
+		fields     []*structMapField
+		matcher    matcher
+	}
+
+	structMapField struct {
+		*xunsafe.Field
+		name     string
+		jsonName string
+		tag      *Tag
+	}
+)
+
+func (s *structMapDiffer) diff(changeLog *ChangeLog, path *Path, from, to interface{}, changeType ChangeType, options *Options) error {
+	structValue, mapValue := reflect.ValueOf(from), reflect.ValueOf(to)
+	if s.reversed {
+		structValue, mapValue = mapValue, structValue
+	}
+	structValue, mapValue = indirect(structValue), indirect(mapValue)
+	if mapValue.IsValid() && mapValue.Kind() != reflect.Map {
+		return fmt.Errorf("invalid map type: %v", mapValue.Type().String())
+	}
+	if structValue.IsValid() {
+		if structValue.Kind() != reflect.Struct {
+			return fmt.Errorf("invalid struct type: %v", structValue.Type().String())
+		}
+		structValue = addressable(structValue)
+	}
+	keys := s.matchKeys(mapValue)
+	for i, field := range s.fields {
+		key, ok := keys[i]
+		if !ok {
+			continue //map does not define the field
+		}
+		var fieldValue, entryValue interface{}
+		if structValue.IsValid() {
+			fieldValue = valueOf(reflect.NewAt(field.Type, field.Pointer(unsafe.Pointer(structValue.UnsafeAddr()))).Elem())
+		}
+		entryValue = valueOf(mapValue.MapIndex(key))
+		if err := s.diffField(changeLog, s.path(path, field), field, fieldValue, entryValue, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *structMapDiffer) diffField(changeLog *ChangeLog, path *Path, field *structMapField, fieldValue, entryValue interface{}, options *Options) error {
+	from, to := fieldValue, entryValue
+	if s.reversed {
+		from, to = to, from
+	}
+	if from == nil && to == nil {
+		return nil
+	}
+	if fieldValue != nil && entryValue != nil {
+		fieldType, entryType := reflect.TypeOf(fieldValue), reflect.TypeOf(entryValue)
+		if isNested(fieldType, entryType, s.config) {
+			fromType, toType := fieldType, entryType
+			if s.reversed {
+				fromType, toType = toType, fromType
+			}
+			differ, err := s.config.registry.Get(fromType, toType, field.tag, WithConfig(s.config))
+			if err != nil {
+				return err
+			}
+			return differ.diff(changeLog, path, from, to, ChangeTypeUpdate, options)
+		}
+	}
+	matched, err := s.matches(fieldValue, entryValue, field.tag)
+	if err != nil {
+		changeLog.AddError(path, err)
+		return nil
+	}
+	if !matched {
+		changeLog.addValue(discoverChangeType(from, to), path, from, to, s.config.timeLayout(field.tag))
+	}
+	return nil
+}
+
+//matches compares struct field value with loosely typed map entry value (i.e. decoded from JSON)
+func (s *structMapDiffer) matches(fieldValue, entryValue interface{}, tag *Tag) (bool, error) {
+	if fieldValue == nil || entryValue == nil {
+		return fieldValue == nil && entryValue == nil, nil
+	}
+	fieldItem, entryItem := indirect(reflect.ValueOf(fieldValue)), indirect(reflect.ValueOf(entryValue))
+	switch {
+	case fieldItem.Type() == entryItem.Type():
+		return s.config.matches(fieldValue, entryValue, tag)
+	case isTimeType(fieldItem.Type()) && entryItem.Kind() == reflect.String:
+		layout := s.config.timeLayout(tag)
+		if layout == "" {
+			layout = time.RFC3339Nano
+		}
+		ts, err := time.Parse(layout, entryItem.String())
+		if err != nil {
+			return false, nil
+		}
+		return s.config.matches(fieldItem.Interface(), ts, tag)
+	case isBasicKind(fieldItem.Kind()) && isBasicKind(entryItem.Kind()):
+		from, to := convertKinds(fieldItem.Interface(), entryItem.Interface())
+		return s.config.matches(from, to, tag)
+	case fieldItem.Kind() == reflect.Slice && entryItem.Kind() == reflect.Slice:
+		if fieldItem.Len() != entryItem.Len() {
+			return false, nil
+		}
+		for i := 0; i < fieldItem.Len(); i++ {
+			matched, err := s.matches(valueOf(fieldItem.Index(i)), valueOf(entryItem.Index(i)), tag)
+			if !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return s.config.matches(fieldValue, entryValue, tag)
+}
+
+//matchKeys returns map keys matched with struct fields positions
+func (s *structMapDiffer) matchKeys(mapValue reflect.Value) map[int]reflect.Value {
+	result := make(map[int]reflect.Value)
+	for _, key := range sortedKeys(mapValue) {
+		match := s.matcher.match(fmt.Sprintf("%v", key.Interface()))
+		if match == nil {
+			continue
+		}
+		for i, field := range s.fields {
+			if field.Field != match.Field {
+				continue
+			}
+			if _, ok := result[i]; !ok {
+				result[i] = key
+			}
+		}
+	}
+	return result
+}
+
+func (s *structMapDiffer) path(parent *Path, field *structMapField) *Path {
+	result := parent.Field(field.name)
+	result.jsonName = field.jsonName
+	return result
+}
+
+//isNested returns true if struct field value and map entry value need to be compared with a nested differ
+func isNested(fieldType, entryType reflect.Type, config *Config) bool {
+	if config.isLeaf(fieldType) || config.isLeaf(entryType) {
+		return false
+	}
+	if structType(fieldType) != nil {
+		return mapType(entryType) != nil
+	}
+	return fieldType == entryType && (mapType(fieldType) != nil || sliceType(fieldType) != nil)
+}
+
+//isStructMap returns true if one type is a struct and the other is a map
+func isStructMap(from, to reflect.Type) bool {
+	return (structType(from) != nil && mapType(to) != nil) || (mapType(from) != nil && structType(to) != nil)
+}
+
+//valueOf returns value interface, nil for invalid or nil value
+func valueOf(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+	}
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	return value.Interface()
+}
+
+func newStructMapDiffer(from, to reflect.Type, config *Config) (*structMapDiffer, error) {
+	result := &structMapDiffer{config: config, structType: structType(from), mapType: mapType(to)}
+	if result.structType == nil || result.mapType == nil {
+		result.reversed = true
+		result.structType, result.mapType = structType(to), mapType(from)
+	}
+	if result.structType == nil || result.mapType == nil {
+		return nil, fmt.Errorf("unsupported struct map types: %s, %s", from.String(), to.String())
+	}
+	xStruct := xunsafe.NewStruct(result.structType)
+	result.matcher.build(xStruct, config)
+	for i := range xStruct.Fields {
+		xField := &xStruct.Fields[i]
+		if result.structType.Field(int(xField.Index)).PkgPath != "" {
+			continue //unexported field
+		}
+		tag, err := ParseTag(xField.Tag.Get(config.TagName))
+		if err != nil {
+			return nil, err
+		}
+		if tag.Ignore {
+			continue
+		}
+		tag.init(config)
+		aField := newField(xField, accessor{}, accessor{}, tag)
+		result.fields = append(result.fields, &structMapField{Field: xField, name: aField.name, jsonName: aField.jsonName, tag: tag})
+	}
+	return result, nil
+}
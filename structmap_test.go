@@ -0,0 +1,79 @@
+package godiff
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffer_StructMap(t *testing.T) {
+
+	type Address struct {
+		City string
+		Zip  string `json:"zip_code"`
+	}
+	type Entity struct {
+		ID        int
+		FirstName string
+		Active    bool
+		Price     float64 `diff:"name=cost"`
+		Tags      []string
+		Updated   time.Time
+		Address   Address
+		Secret    string `diff:"-"`
+	}
+
+	ts := time.Date(2023, 5, 20, 10, 30, 0, 0, time.UTC)
+	entity := Entity{ID: 1, FirstName: "Bob", Active: true, Price: 1.5, Tags: []string{"a"}, Updated: ts, Address: Address{City: "LA", Zip: "90001"}, Secret: "x"}
+	decode := func(text string) map[string]interface{} {
+		result := map[string]interface{}{}
+		_ = json.Unmarshal([]byte(text), &result)
+		return result
+	}
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		expect      *ChangeLog
+	}{
+		{
+			description: "partial update - no changes",
+			from:        entity,
+			to:          decode(`{"id":1,"first_name":"Bob","active":true,"cost":1.5,"tags":["a"],"updated":"2023-05-20T10:30:00Z","secret":"y","unknown":1}`),
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "partial update - changes",
+			from:        entity,
+			to:          decode(`{"ID":2,"firstName":"Alice","Tags":["a","b"],"Address":{"city":"SF","zip_code":"94016"}}`),
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("ID"), From: 1, To: 2.0},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("FirstName"), From: "Bob", To: "Alice"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Tags"), From: []string{"a"}, To: []interface{}{"a", "b"}},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Address").Field("City"), From: "LA", To: "SF"},
+				{Type: ChangeTypeUpdate, Path: &Path{Kind: PathKinField, Name: "Zip", jsonName: "zip_code", Path: (&Path{}).Field("Address")}, From: "90001", To: "94016"},
+			}},
+		},
+		{
+			description: "map to struct",
+			from:        decode(`{"cost":2,"active":null}`),
+			to:          &entity,
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeCreate, Path: (&Path{}).Field("Active"), To: true},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("cost"), From: 2.0, To: 1.5},
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+	}
+}
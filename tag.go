@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //Tag represents a tag
@@ -18,10 +19,17 @@ type Tag struct {
 	Whitespace   string
 	IndexBy      string
 	Sort         bool
+	SortBy       string
+	SortDesc     bool
+	Set          bool
 	TimeLayout   string
 	Precision    *int
 	Ignore       bool
 	NullifyEmpty *bool
+	Comparator   string
+	Within       time.Duration
+	Align        string
+	decoded      bool //values are decoded from pair or item separated text
 }
 
 func (t *Tag) decodable() bool {
@@ -40,6 +48,30 @@ func (t *Tag) init(config *Config) {
 	}
 }
 
+//parseSort parses sort tag value: true, false or field name (dotted path) followed by optional asc or desc direction
+func (t *Tag) parseSort(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("invalid sort: %v", value)
+	}
+	if sorted, err := strconv.ParseBool(fields[0]); err == nil {
+		t.Sort = sorted
+	} else {
+		t.Sort = true
+		t.SortBy = fields[0]
+	}
+	if len(fields) == 2 {
+		switch strings.ToLower(fields[1]) {
+		case "asc":
+		case "desc":
+			t.SortDesc = true
+		default:
+			return fmt.Errorf("invalid sort direction: %v", fields[1])
+		}
+	}
+	return nil
+}
+
 func (t *Tag) removeWhitespace(value string) string {
 	if t.Whitespace == "" {
 		return value
@@ -80,6 +112,8 @@ func ParseTag(tagString string) (*Tag, error) {
 				tag.Ignore = true
 			case "name":
 				tag.Name = strings.TrimSpace(nv[1])
+			case "set":
+				tag.Set, _ = strconv.ParseBool(strings.TrimSpace(nv[1]))
 			case "indexby":
 				tag.IndexBy = strings.TrimSpace(nv[1])
 			case "timelayout":
@@ -99,7 +133,22 @@ func ParseTag(tagString string) (*Tag, error) {
 			case "itemseparator":
 				tag.ItemSeparator = strings.TrimSpace(nv[1])
 			case "sort":
-				tag.Sort, _ = strconv.ParseBool(strings.TrimSpace(nv[1]))
+				if err := tag.parseSort(nv[1]); err != nil {
+					return nil, err
+				}
+			case "within":
+				within, err := time.ParseDuration(strings.TrimSpace(nv[1]))
+				if err != nil {
+					return nil, fmt.Errorf("invalid within: %w, %v", err, nv[1])
+				}
+				tag.Within = within
+			case "align":
+				tag.Align = strings.ToLower(strings.TrimSpace(nv[1]))
+				if tag.Align != AlignLCS {
+					return nil, fmt.Errorf("unsupported align: %v", nv[1])
+				}
+			case "comparator":
+				tag.Comparator = strings.TrimSpace(nv[1])
 			}
 			continue
 		case 1:
@@ -0,0 +1,58 @@
+package godiff
+
+import "time"
+
+//timeLayout returns tag time layout, or config time layout if tag does not define one
+func (c *Config) timeLayout(tag *Tag) string {
+	if tag != nil && tag.TimeLayout != "" {
+		return tag.TimeLayout
+	}
+	return c.TimeLayout
+}
+
+//matchesTime returns true if time values represent the same instant, within tolerance or at time layout granularity,
+//ok is false if values are not time
+func matchesTime(from, to interface{}, layout string, within time.Duration) (matched bool, ok bool) {
+	fromTime, ok := asTime(from)
+	if !ok {
+		return false, false
+	}
+	toTime, ok := asTime(to)
+	if !ok {
+		return false, false
+	}
+	switch {
+	case within > 0:
+		delta := fromTime.Sub(toTime)
+		if delta < 0 {
+			delta = -delta
+		}
+		return delta <= within, true
+	case layout != "":
+		return fromTime.UTC().Format(layout) == toTime.UTC().Format(layout), true
+	}
+	return fromTime.Equal(toTime), true
+}
+
+func asTime(value interface{}) (time.Time, bool) {
+	switch actual := value.(type) {
+	case time.Time:
+		return actual, true
+	case *time.Time:
+		if actual != nil {
+			return *actual, true
+		}
+	}
+	return time.Time{}, false
+}
+
+//formatTime returns time value formatted with the layout, other values are returned as is
+func formatTime(value interface{}, layout string) interface{} {
+	if layout == "" {
+		return value
+	}
+	if ts, ok := asTime(value); ok {
+		return ts.Format(layout)
+	}
+	return value
+}
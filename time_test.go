@@ -0,0 +1,80 @@
+package godiff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffer_Time(t *testing.T) {
+
+	type Event struct {
+		ID      int
+		Created time.Time
+		Updated time.Time    `diff:"within=1s"`
+		Day     time.Time    `diff:"timeLayout=2006-01-02"`
+		Times   []*time.Time `diff:"timeLayout=2006-01-02 15:04"`
+	}
+
+	ts := time.Date(2023, 5, 20, 10, 30, 15, 123456789, time.UTC)
+	local := ts.In(time.FixedZone("PDT", -7*3600))
+	truncated := ts.Truncate(time.Second)
+	nextDay := ts.Add(24 * time.Hour)
+	nextMinute := ts.Add(time.Minute)
+
+	var testCases = []struct {
+		description string
+		from        interface{}
+		to          interface{}
+		options     []ConfigOption
+		expect      *ChangeLog
+		records     []*ChangeRecord
+	}{
+		{
+			description: "same instant",
+			from:        Event{ID: 1, Created: ts, Updated: ts, Day: ts, Times: []*time.Time{&ts}},
+			to:          Event{ID: 1, Created: local, Updated: truncated, Day: ts.Add(time.Hour), Times: []*time.Time{&truncated}},
+			expect:      &ChangeLog{},
+		},
+		{
+			description: "changed instant",
+			from:        Event{ID: 1, Created: ts, Updated: ts, Day: ts, Times: []*time.Time{&ts}},
+			to:          Event{ID: 1, Created: truncated, Updated: nextMinute, Day: nextDay, Times: []*time.Time{&nextMinute}},
+			expect: &ChangeLog{Changes: []*Change{
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Created"), From: ts, To: truncated},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Updated"), From: ts, To: nextMinute},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Day"), From: ts, To: nextDay, timeLayout: "2006-01-02"},
+				{Type: ChangeTypeUpdate, Path: (&Path{}).Field("Times").Element(0), From: &ts, To: &nextMinute, timeLayout: "2006-01-02 15:04"},
+			}},
+			records: []*ChangeRecord{
+				{Path: "Created", Change: "update", From: ts, To: truncated},
+				{Path: "Updated", Change: "update", From: ts, To: nextMinute},
+				{Path: "Day", Change: "update", From: "2023-05-20", To: "2023-05-21"},
+				{Path: "Times[0]", Change: "update", From: "2023-05-20 10:30", To: "2023-05-20 10:31"},
+			},
+		},
+		{
+			description: "config time layout",
+			from:        []time.Time{ts},
+			to:          []time.Time{nextDay},
+			options:     []ConfigOption{WithTimeLayout("2006")},
+			expect:      &ChangeLog{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		differ, err := New(reflect.TypeOf(testCase.from), reflect.TypeOf(testCase.to), testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		changeLog := differ.Diff(testCase.from, testCase.to)
+		assert.EqualValues(t, testCase.expect, changeLog, testCase.description)
+		if testCase.records != nil {
+			assert.EqualValues(t, testCase.records, changeLog.ToChangeRecords("", "", ""), testCase.description)
+		}
+	}
+
+	_, err := ParseTag("within=abc")
+	assert.NotNil(t, err)
+}
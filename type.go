@@ -25,6 +25,16 @@ func sliceType(p reflect.Type) reflect.Type {
 	return nil
 }
 
+func arrayType(p reflect.Type) reflect.Type {
+	switch p.Kind() {
+	case reflect.Ptr:
+		return arrayType(p.Elem())
+	case reflect.Array:
+		return p
+	}
+	return nil
+}
+
 func interfaceType(p reflect.Type) reflect.Type {
 	switch p.Kind() {
 	case reflect.Ptr: